@@ -15,11 +15,25 @@ func init() {
 	pkg.CLI.Flags().DurationVarP(&pkg.LogsSince, "logs-since", "l", 1*time.Hour, "TODO if zero, all")
 	pkg.CLI.Flags().DurationVarP(&pkg.Timeout, "timeout", "t", 0, "TODO if zero, no timeout")
 	pkg.CLI.Flags().BoolVarP(&pkg.SkipTLS, "skip-tls", "s", false, "TODO")
+	pkg.CLI.Flags().StringVar(&pkg.Kubeconfig, "kubeconfig", "", "Path to kubeconfig file. If empty, uses KUBECONFIG environment variable, ~/.kube/config or in cluster config")
+	pkg.CLI.Flags().StringSliceVar(&pkg.KubeContexts, "context", nil, "Kubeconfig contexts to gather from, can be repeated. Clusters are gathered concurrently into sibling clusters/<id>/ dirs, with a cross-cluster summary. If empty, uses current context")
+	pkg.CLI.Flags().StringVar(&pkg.DestDir, "dest-dir", "must-gather", "Directory to write must-gather output to")
+	pkg.CLI.Flags().StringVar(&pkg.MaxSize, "max-size", "", "Maximum size of must-gather output, like 500Mi or 1G, split evenly between clusters of --context. Summary and OADP resources (including all Backups, Restores and DeleteBackupRequests) are always collected and never budgeted, then logs (truncated if large), then oc adm inspect output. If empty, no limit")
+	pkg.CLI.Flags().StringVar(&pkg.Archive, "archive", "", "Also write must-gather output to an archive, tar.gz or tar.zst. Not needed with oc adm must-gather, that handles transfer")
+	pkg.CLI.Flags().StringVar(&pkg.ArchiveChunkSize, "archive-chunk-size", "", "Split archive in chunks of at most this size, like 100Mi, for upload portals with size caps. An index of which chunk holds which files is written to <dest-dir>-index.json, beside <dest-dir>")
+	pkg.CLI.Flags().StringVar(&pkg.SupportMatrixPath, "support-matrix", "", "Path to a support matrix YAML file, to use instead of the one embedded in OADP must-gather")
 	// pkg.CLI.Flags().BoolVarP(&essentialOnly, "essential-only", "e", false, "TODO")
 	pkg.CLI.Flags().BoolP("help", "h", false, "Show OADP Must-gather help message.")
 	// TODO JSON output in the future?
 
 	pkg.CLI.SetHelpCommand(&cobra.Command{Hidden: true, Use: "mateus"})
+	pkg.CLI.AddCommand(pkg.DiffCLI)
+
+	pkg.MigrateCheckCLI.Flags().StringVar(&pkg.SourceContext, "source", "", "Kubeconfig context of cluster where Backup was taken")
+	pkg.MigrateCheckCLI.Flags().StringVar(&pkg.TargetContext, "target", "", "Kubeconfig context of cluster where Backup will be restored")
+	pkg.MigrateCheckCLI.Flags().StringVar(&pkg.BackupName, "backup", "", "Name of Backup in source cluster")
+	pkg.MigrateCheckCLI.Flags().StringVar(&pkg.Kubeconfig, "kubeconfig", "", "Path to kubeconfig file. If empty, uses KUBECONFIG environment variable or ~/.kube/config")
+	pkg.CLI.AddCommand(pkg.MigrateCheckCLI)
 }
 
 func main() {
@@ -0,0 +1,246 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	TarGz  = "tar.gz"
+	TarZst = "tar.zst"
+)
+
+var Formats = []string{TarGz, TarZst}
+
+const (
+	tarBlockSize = 512
+	// upper bound of tar bytes added to a file: PAX header for long names, header and padding of content
+	tarFileOverhead = 4 * tarBlockSize
+	// tar trailer (two zero blocks) and compressor flush and footer bytes written when chunk is closed
+	chunkCloseOverhead = 2*tarBlockSize + 1024
+)
+
+// compressedUpperBound returns an upper bound of compressed bytes of a file in tar, incompressible content is stored
+// in raw blocks, that add a few bytes per block
+func compressedUpperBound(size int64) int64 {
+	return size + size/1024 + tarFileOverhead
+}
+
+// Chunk is an archive file and the files it holds
+type Chunk struct {
+	Name  string   `json:"name"`
+	Size  int64    `json:"size"`
+	Files []string `json:"files"`
+}
+
+// Index lists which chunk holds which files
+type Index struct {
+	Format string  `json:"format"`
+	Chunks []Chunk `json:"chunks"`
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.writer.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// chunkWriter writes one archive file
+type chunkWriter struct {
+	file       *os.File
+	counter    *countingWriter
+	compressor compressor
+	tar        *tar.Writer
+	chunk      Chunk
+	// upper bound of compressed bytes of files added since last flush
+	pending int64
+}
+
+func newChunkWriter(path string, format string) (*chunkWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	counter := &countingWriter{writer: file}
+	var compressorWriter compressor
+	switch format {
+	case TarGz:
+		compressorWriter = gzip.NewWriter(counter)
+	case TarZst:
+		compressorWriter, err = zstd.NewWriter(counter)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+	default:
+		file.Close()
+		return nil, fmt.Errorf("unknown archive format '%s', use one of %s", format, strings.Join(Formats, ", "))
+	}
+	return &chunkWriter{
+		file:       file,
+		counter:    counter,
+		compressor: compressorWriter,
+		tar:        tar.NewWriter(compressorWriter),
+		chunk:      Chunk{Name: filepath.Base(path)},
+	}, nil
+}
+
+func (c *chunkWriter) add(path string, name string, info fs.FileInfo) error {
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	err = c.tar.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(c.tar, file)
+	if err != nil {
+		return err
+	}
+	c.pending += compressedUpperBound(info.Size())
+	c.chunk.Files = append(c.chunk.Files, name)
+	return nil
+}
+
+// fits returns if a file of size fits in chunk of chunkSize. Compressor is only flushed (which resets its compression context)
+// when the upper bound of chunk size does not fit, so counter has the compressed size written so far
+func (c *chunkWriter) fits(size int64, chunkSize int64) (bool, error) {
+	if c.counter.count+c.pending+compressedUpperBound(size)+chunkCloseOverhead <= chunkSize {
+		return true, nil
+	}
+	if c.pending != 0 {
+		err := c.tar.Flush()
+		if err != nil {
+			return false, err
+		}
+		err = c.compressor.Flush()
+		if err != nil {
+			return false, err
+		}
+		c.pending = 0
+	}
+	return c.counter.count+compressedUpperBound(size)+chunkCloseOverhead <= chunkSize, nil
+}
+
+func (c *chunkWriter) close() (Chunk, error) {
+	err := c.tar.Close()
+	if err == nil {
+		err = c.compressor.Close()
+	}
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	c.chunk.Size = c.counter.count
+	return c.chunk, err
+}
+
+func chunkPath(archivePath string, format string, number int) string {
+	return fmt.Sprintf("%s-%d.%s", archivePath, number, format)
+}
+
+// Create archives srcDir in <archivePath>.<format>. If chunkSize is not zero, splits files in chunks
+// <archivePath>-<number>.<format> of at most chunkSize bytes (unless a single file is bigger than it).
+// An index of which chunk holds which files is written to <archivePath>-index.json
+func Create(srcDir string, archivePath string, format string, chunkSize int64) (*Index, error) {
+	absSrcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	absArchiveDir, err := filepath.Abs(filepath.Dir(archivePath))
+	if err != nil {
+		return nil, err
+	}
+	if relativePath, err := filepath.Rel(absSrcDir, absArchiveDir); err == nil &&
+		relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("archive '%s' can not be written inside archived directory '%s'", archivePath, srcDir)
+	}
+	index := &Index{Format: format}
+	chunkNumber := 1
+	path := archivePath + "." + format
+	if chunkSize != 0 {
+		path = chunkPath(archivePath, format, chunkNumber)
+	}
+	current, err := newChunkWriter(path, format)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Dir(filepath.Clean(srcDir))
+	err = filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if chunkSize != 0 && len(current.chunk.Files) != 0 {
+			fits, err := current.fits(info.Size(), chunkSize)
+			if err != nil {
+				return err
+			}
+			if fits {
+				return current.add(path, filepath.ToSlash(name), info)
+			}
+			chunk, err := current.close()
+			if err != nil {
+				return err
+			}
+			index.Chunks = append(index.Chunks, chunk)
+			chunkNumber++
+			current, err = newChunkWriter(chunkPath(archivePath, format, chunkNumber), format)
+			if err != nil {
+				return err
+			}
+		}
+		return current.add(path, filepath.ToSlash(name), info)
+	})
+	chunk, closeErr := current.close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	index.Chunks = append(index.Chunks, chunk)
+
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// TODO permission
+	err = os.WriteFile(archivePath+"-index.json", content, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
@@ -0,0 +1,194 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// writeFiles writes files of random (so incompressible) content in dir, by name
+func writeFiles(t *testing.T, dir string, sizes map[string]int) {
+	t.Helper()
+	random := rand.New(rand.NewSource(1))
+	for name, size := range sizes {
+		content := make([]byte, size)
+		random.Read(content)
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, content, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// chunkFiles returns names of files in a chunk archive
+func chunkFiles(t *testing.T, path string, format string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var reader io.Reader
+	switch format {
+	case TarGz:
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = gzipReader
+	case TarZst:
+		zstdReader, err := zstd.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zstdReader.Close()
+		reader = zstdReader
+	}
+	var names []string
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatalf("unable to read %s: %v", path, err)
+		}
+		names = append(names, header.Name)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	const chunkSize = 64 * 1024
+	tests := []struct {
+		name      string
+		chunkSize int64
+		sizes     map[string]int
+		// files that must be alone in their chunk
+		alone []string
+	}{
+		{
+			name:  "no chunks",
+			sizes: map[string]int{"a": 30 * 1024, "b/c": 30 * 1024, "b/d": 1},
+		},
+		{
+			name:      "files spread across chunks",
+			chunkSize: chunkSize,
+			sizes:     map[string]int{"a": 20 * 1024, "b": 20 * 1024, "c/d": 20 * 1024, "c/e": 20 * 1024, "f": 20 * 1024, "g": 0},
+		},
+		{
+			name:      "oversized file",
+			chunkSize: chunkSize,
+			sizes:     map[string]int{"a": 10 * 1024, "b": 3 * chunkSize, "c": 10 * 1024},
+			alone:     []string{"b"},
+		},
+	}
+	for _, format := range Formats {
+		for _, test := range tests {
+			t.Run(format+" "+test.name, func(t *testing.T) {
+				dir := t.TempDir()
+				srcDir := filepath.Join(dir, "must-gather")
+				writeFiles(t, srcDir, test.sizes)
+				archivePath := filepath.Join(dir, "must-gather")
+
+				index, err := Create(srcDir, archivePath, format, test.chunkSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if index.Format != format {
+					t.Errorf("Format = %s, want %s", index.Format, format)
+				}
+
+				content, err := os.ReadFile(archivePath + "-index.json")
+				if err != nil {
+					t.Fatal(err)
+				}
+				var written Index
+				err = json.Unmarshal(content, &written)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.EqualFunc(written.Chunks, index.Chunks, func(a Chunk, b Chunk) bool {
+					return a.Name == b.Name && a.Size == b.Size && slices.Equal(a.Files, b.Files)
+				}) {
+					t.Errorf("written index %v, want %v", written, index)
+				}
+
+				if test.chunkSize == 0 {
+					if len(index.Chunks) != 1 || index.Chunks[0].Name != "must-gather."+format {
+						t.Fatalf("Chunks = %v, want a single must-gather.%s", index.Chunks, format)
+					}
+				} else if len(index.Chunks) < 2 {
+					t.Fatalf("Chunks = %v, want more than one chunk", index.Chunks)
+				}
+
+				var archived []string
+				for _, chunk := range index.Chunks {
+					path := filepath.Join(dir, chunk.Name)
+					info, err := os.Stat(path)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if info.Size() != chunk.Size {
+						t.Errorf("chunk %s has %d bytes, index says %d", chunk.Name, info.Size(), chunk.Size)
+					}
+					if files := chunkFiles(t, path, format); !slices.Equal(files, chunk.Files) {
+						t.Errorf("chunk %s holds %v, index says %v", chunk.Name, files, chunk.Files)
+					}
+					oversized := slices.ContainsFunc(test.alone, func(name string) bool {
+						return slices.Contains(chunk.Files, filepath.ToSlash(filepath.Join("must-gather", name)))
+					})
+					if oversized && len(chunk.Files) != 1 {
+						t.Errorf("oversized file must be alone in chunk %s, it holds %v", chunk.Name, chunk.Files)
+					}
+					if test.chunkSize != 0 && !oversized && chunk.Size > test.chunkSize {
+						t.Errorf("chunk %s has %d bytes, more than chunk size %d", chunk.Name, chunk.Size, test.chunkSize)
+					}
+					archived = append(archived, chunk.Files...)
+				}
+
+				var want []string
+				for name := range test.sizes {
+					want = append(want, filepath.ToSlash(filepath.Join("must-gather", name)))
+				}
+				slices.Sort(want)
+				slices.Sort(archived)
+				if !slices.Equal(archived, want) {
+					t.Errorf("archived %v, want each of %v once", archived, want)
+				}
+			})
+		}
+	}
+}
+
+func TestCreateInsideSrcDir(t *testing.T) {
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]int{"a": 1})
+	for _, archivePath := range []string{
+		filepath.Join(srcDir, "archive"),
+		filepath.Join(srcDir, "sub", "archive"),
+		srcDir + string(filepath.Separator) + ".",
+	} {
+		_, err := Create(srcDir, archivePath, TarGz, 0)
+		if err == nil {
+			t.Errorf("Create() with archive path %s inside %s did not return error", archivePath, srcDir)
+		}
+	}
+	_, err := Create(srcDir, srcDir, TarGz, 0)
+	if err != nil {
+		t.Errorf("Create() with archive beside %s returned error: %v", srcDir, err)
+	}
+}
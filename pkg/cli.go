@@ -1,8 +1,13 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"path/filepath"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
@@ -10,32 +15,53 @@ import (
 	openshiftconfigv1 "github.com/openshift/api/config/v1"
 	oadpv1alpha1 "github.com/openshift/oadp-operator/api/v1alpha1"
 	ocadminspect "github.com/openshift/oc/pkg/cli/admin/inspect"
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	velerov2alpha1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v2alpha1"
+	"github.com/vmware-tanzu/velero/pkg/repository"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/archive"
 	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gvk"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/knownissues"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/manifest"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/supportmatrix"
 	"github.com/mateusoliveira43/oadp-must-gather/pkg/templates"
 )
 
 const (
-	mustGatherVersion    = "dev-Jan-30-2025"
-	oadpOpenShiftVersion = "4.18"
+	mustGatherVersion = "dev-Jan-30-2025"
 	// TODO <this-image> const
 )
 
 // TODO which errors should make must-gather exit earlier?
 
 var (
-	LogsSince time.Duration
-	Timeout   time.Duration
-	SkipTLS   bool
+	LogsSince         time.Duration
+	Timeout           time.Duration
+	SkipTLS           bool
+	SupportMatrixPath string
+	Kubeconfig        string
+	KubeContexts      []string
+	DestDir           string
+	MaxSize           string
+	Archive           string
+	ArchiveChunkSize  string
 	// essentialOnly bool
 
 	CLI = &cobra.Command{
@@ -56,286 +82,687 @@ TODO`,
   # TODO
   oc adm must-gather --image=<this-image> -- /usr/bin/gather --skip-tls --timeout <time>
 
-  # TODO metrics dump`,
+  # TODO metrics dump
+
+  # run outside of oc adm must-gather, against a kubeconfig context
+  go run cmd/main.go --kubeconfig ~/.kube/config --context <context> --dest-dir <dir>
+
+  # gather clusters of several contexts concurrently, with a cross-cluster summary
+  go run cmd/main.go --context <source-context> --context <target-context>`,
 		SilenceErrors: true,
 		SilenceUsage:  true,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			// TODO test flags
 			// fmt.Printf("logsSince %#v\n", LogsSince)
-
-			clusterConfig := config.GetConfigOrDie()
-			// https://github.com/openshift/oc/blob/46db7c2bce5a57e3c3d9347e7e1e107e61dbd306/pkg/cli/admin/inspect/inspect.go#L142
-			clusterConfig.QPS = 999999
-			clusterConfig.Burst = 999999
-
-			clusterClient, err := client.New(clusterConfig, client.Options{})
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while creating Go client: %v\n", err)
-				return err
-			}
-
-			// in what versions of OCP must must-gather work? be careful about API versions update?
-			err = openshiftconfigv1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
-			}
-			err = operatorsv1alpha1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
-			}
-			err = storagev1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
-			}
-			err = volumesnapshotv1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
-			}
-			err = corev1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
-			}
-			// OADP CRDs
-			err = oadpv1alpha1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
+			flags := map[string]string{}
+			cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+				flags[flag.Name] = flag.Value.String()
+			})
+			manifest.Start(mustGatherVersion, flags)
+
+			var maxSize int64
+			if len(MaxSize) != 0 {
+				quantity, err := resource.ParseQuantity(MaxSize)
+				if err != nil || quantity.Value() <= 0 {
+					err = fmt.Errorf("invalid --max-size '%s', use a positive size like 500Mi or 1G", MaxSize)
+					fmt.Printf("Exiting OADP must-gather: %v\n", err)
+					return err
+				}
+				maxSize = quantity.Value()
 			}
-			err = nac1alpha1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
+			if len(Archive) != 0 && !slices.Contains(archive.Formats, Archive) {
+				err := fmt.Errorf("invalid --archive '%s', use one of %s", Archive, strings.Join(archive.Formats, ", "))
+				fmt.Printf("Exiting OADP must-gather: %v\n", err)
 				return err
 			}
-			err = velerov1.AddToScheme(clusterClient.Scheme())
-			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
-				return err
+			var archiveChunkSize int64
+			if len(ArchiveChunkSize) != 0 {
+				chunkSize, err := resource.ParseQuantity(ArchiveChunkSize)
+				if err != nil || chunkSize.Value() <= 0 {
+					err = fmt.Errorf("invalid --archive-chunk-size '%s', use a positive size like 100Mi or 1G", ArchiveChunkSize)
+					fmt.Printf("Exiting OADP must-gather: %v\n", err)
+					return err
+				}
+				archiveChunkSize = chunkSize.Value()
 			}
-			err = velerov2alpha1.AddToScheme(clusterClient.Scheme())
+			manifest.StartStep("", "setup")
+
+			supportMatrix, err := supportmatrix.Load(SupportMatrixPath)
 			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while adding to scheme: %v\n", err)
+				fmt.Printf("Exiting OADP must-gather, an error happened while loading support matrix: %v\n", err)
 				return err
 			}
 
-			clusterVersionList := &openshiftconfigv1.ClusterVersionList{}
-			err = gather.AllResources(clusterClient, clusterVersionList)
+			knownIssues, err := knownissues.Load()
 			if err != nil {
-				fmt.Printf("Exiting OADP must-gather, an error happened while gathering ClusterVersion: %v\n", err)
+				fmt.Printf("Exiting OADP must-gather, an error happened while loading known issues: %v\n", err)
 				return err
 			}
-			if len(clusterVersionList.Items) == 0 {
-				err = fmt.Errorf("no ClusterVersion found in cluster")
-				fmt.Printf("Exiting OADP must-gather, an error happened while gathering ClusterVersion: %v\n", err)
-				return err
+
+			kubeContexts := KubeContexts
+			if len(kubeContexts) == 0 {
+				// current context
+				kubeContexts = []string{""}
 			}
-			clusterVersion := &clusterVersionList.Items[0]
-			clusterID := string(clusterVersion.Spec.ClusterID[:8])
-
-			// for now, lest keep the folder structure as it is
-			//     must-gather/clusters/<id>/cluster-scoped-resources/apiextensions.k8s.io/customresourcedefinitions
-			//     must-gather/clusters/<id>/namespaces/<name>/velero.io/<name>
-			//     must-gather/clusters/<id>/namespaces/<name>/oadp.openshift.io/<name>
-			// otherwise may break `omg` usage. ref https://github.com/openshift/oadp-operator/pull/1269
-			outputPath := fmt.Sprintf("must-gather/clusters/%s/", clusterID)
-
-			var resourcesToGather []client.ObjectList
-			infrastructureList := &openshiftconfigv1.InfrastructureList{}
-			nodeList := &corev1.NodeList{}
-			clusterServiceVersionList := &operatorsv1alpha1.ClusterServiceVersionList{}
-			// TODO when Velero/OADP API updates, how to handle? use dynamic client instead?
-			dataProtectionApplicationList := &oadpv1alpha1.DataProtectionApplicationList{}
-			cloudStorageList := &oadpv1alpha1.CloudStorageList{}
-			backupStorageLocationList := &velerov1.BackupStorageLocationList{}
-			volumeSnapshotLocationList := &velerov1.VolumeSnapshotLocationList{}
-			backupList := &velerov1.BackupList{}
-			restoreList := &velerov1.RestoreList{}
-			scheduleList := &velerov1.ScheduleList{}
-			backupRepositoryList := &velerov1.BackupRepositoryList{}
-			dataUploadList := &velerov2alpha1.DataUploadList{}
-			dataDownloadList := &velerov2alpha1.DataDownloadList{}
-			podVolumeBackupList := &velerov1.PodVolumeBackupList{}
-			podVolumeRestoreList := &velerov1.PodVolumeRestoreList{}
-			downloadRequestList := &velerov1.DownloadRequestList{}
-			deleteBackupRequestList := &velerov1.DeleteBackupRequestList{}
-			serverStatusRequestList := &velerov1.ServerStatusRequestList{}
-
-			storageClassList := &storagev1.StorageClassList{}
-			volumeSnapshotClassList := &volumesnapshotv1.VolumeSnapshotClassList{}
-			csiDriverList := &storagev1.CSIDriverList{}
-			resourcesToGather = append(resourcesToGather,
-				infrastructureList,
-				nodeList,
-				clusterServiceVersionList,
-				dataProtectionApplicationList,
-				cloudStorageList,
-				backupStorageLocationList,
-				volumeSnapshotLocationList,
-				backupList,
-				restoreList,
-				scheduleList,
-				backupRepositoryList,
-				dataUploadList,
-				dataDownloadList,
-				podVolumeBackupList,
-				podVolumeRestoreList,
-				downloadRequestList,
-				deleteBackupRequestList,
-				serverStatusRequestList,
-
-				storageClassList,
-				volumeSnapshotClassList,
-				csiDriverList,
-			)
-			for _, resource := range resourcesToGather {
-				// TODO  do this part in parallel?
-				err = gather.AllResources(clusterClient, resource)
-				if err != nil {
-					fmt.Println(err)
+			for index, kubeContext := range kubeContexts {
+				if slices.Contains(kubeContexts[:index], kubeContext) {
+					err = fmt.Errorf("--context '%s' is repeated", kubeContext)
+					fmt.Printf("Exiting OADP must-gather: %v\n", err)
+					return err
 				}
 			}
-
-			if len(infrastructureList.Items) == 0 {
-				fmt.Println(fmt.Errorf("no Infrastructure found in cluster"))
-			}
-			infrastructure := &infrastructureList.Items[0]
-
-			if len(nodeList.Items) == 0 {
-				fmt.Println(fmt.Errorf("no Node found in cluster"))
+			templates.SetSizeBudget(maxSize, len(kubeContexts))
+			clusters := &gatheredClusters{contexts: map[string]string{}}
+			overviews := make([]*templates.ClusterOverview, len(kubeContexts))
+			// by default, oc adm inspect exits on errors
+			kcmdutil.BehaviorOnFatal(func(message string, _ int) {
+				panic(ocAdmInspectFatal{message: message})
+			})
+			defer kcmdutil.DefaultBehaviorOnFatal()
+			var wg sync.WaitGroup
+			for index, kubeContext := range kubeContexts {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					overview, err := gatherCluster(kubeContext, clusters, supportMatrix, knownIssues)
+					if err != nil {
+						manifest.RecordFailure("", "gather context "+kubeContext, err)
+						overview = &templates.ClusterOverview{Context: kubeContext, Error: err}
+					}
+					overviews[index] = overview
+				}()
 			}
+			wg.Wait()
 
-			// get namespaces with OADP installs
-
-			// subscriptionList := &operatorsv1alpha1.SubscriptionList{}
-			// err = clusterClient.List(context.Background(), subscriptionList)
-			// if err != nil {
-			// 	fmt.Println(err)
-			// }
-			// for _, sub := range subscriptionList.Items {
-			// 	// prod? "redhat-oadp-operator"
-			// 	// other packages that should be important for us?
-			// 	// dev? "oadp-operator" https://github.com/openshift/oadp-operator/blob/5601dcfd0a07468f496ddb70ab570ccff1b4f0cc/bundle/metadata/annotations.yaml#L6
-			// 	fmt.Printf("Found '%v' operator version '%v' installed in '%v' namespace\n", sub.Spec.Package, sub.Spec.StartingCSV, sub.Namespace)
-			// }
-
-			if len(clusterServiceVersionList.Items) == 0 {
-				fmt.Println(fmt.Errorf("no ClusterServiceVersion found in cluster"))
+			var errs []error
+			for _, overview := range overviews {
+				errs = append(errs, overview.Error)
 			}
-			oadpOperatorsText := ""
-			foundOADP := false
-			foundRelatedProducts := false
-			importantCSVsByNamespace := map[string][]operatorsv1alpha1.ClusterServiceVersion{}
-
-			// ?Managed Velero operator? only available in ROSA? https://github.com/openshift/managed-velero-operator
-			//
-			// ?IBM Fusion?
-			//
-			// ?Dell Power Protect?
-			//
-			// upstream velero?
-			relatedProducts := []string{"OpenShift Virtualization", "Advanced Cluster Management for Kubernetes", "Submariner"}
-			communityProducts := []string{"KubeVirt HyperConverged Cluster Operator"}
-
-			for _, csv := range clusterServiceVersionList.Items {
-				// OADP dev, community and prod operators have same spec.displayName
-				if csv.Spec.DisplayName == "OADP Operator" {
-					oadpOperatorsText += fmt.Sprintf("Found **%v** version **%v** installed in **%v** namespace\n\n", csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace)
-					foundOADP = true
-					importantCSVsByNamespace[csv.Namespace] = append(importantCSVsByNamespace[csv.Namespace], csv)
-				}
-				if slices.Contains(relatedProducts, csv.Spec.DisplayName) {
-					oadpOperatorsText += fmt.Sprintf("Found related product **%v** version **%v** installed in **%v** namespace\n\n", csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace)
-					foundRelatedProducts = true
-					importantCSVsByNamespace[csv.Namespace] = append(importantCSVsByNamespace[csv.Namespace], csv)
-				}
-				if slices.Contains(communityProducts, csv.Spec.DisplayName) {
-					oadpOperatorsText += fmt.Sprintf("⚠️ Found related product **%v (Community)** version **%v** installed in **%v** namespace\n\n", csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace)
-					foundRelatedProducts = true
-					importantCSVsByNamespace[csv.Namespace] = append(importantCSVsByNamespace[csv.Namespace], csv)
+			if len(kubeContexts) > 1 {
+				err = templates.WriteClustersSummary(DestDir, mustGatherVersion, overviews)
+				if err != nil {
+					fmt.Printf("Error occurred while writing clusters summary: %v\n", err)
+					errs = append(errs, err)
 				}
 			}
-
-			// oc adm inspect --dest-dir must-gather/clusters/${clusterID} ns/${ns}
-			if len(importantCSVsByNamespace) != 0 {
-				ocAdmInspect := ocadminspect.NewInspectOptions(genericiooptions.NewTestIOStreamsDiscard())
-				ocAdmInspect.DestDir = outputPath
-				ocAdmInspectNamespaces := []string{}
-				for namespace := range importantCSVsByNamespace {
-					ocAdmInspectNamespaces = append(ocAdmInspectNamespaces, "ns/"+namespace)
-				}
-
-				// https://github.com/openshift/oc/blob/ae1bd9e4a75b8ab617a569e5c8e1a0d7285a16f6/pkg/cli/admin/inspect/inspect.go#L108
-				err = ocAdmInspect.Complete(ocAdmInspectNamespaces)
+			// files not written by must-gather Go code are from oc adm inspect
+			err = manifest.Write(DestDir, "oc adm inspect")
+			if err != nil {
+				fmt.Printf("Error occurred while writing manifest: %v\n", err)
+				return err
+			}
+			if len(Archive) != 0 {
+				// archive is written beside dest dir, so it does not include itself
+				destDir, err := filepath.Abs(DestDir)
 				if err != nil {
-					fmt.Println(err)
+					fmt.Printf("Error occurred while creating archive: %v\n", err)
+					return err
 				}
-				err = ocAdmInspect.Validate()
+				index, err := archive.Create(destDir, destDir, Archive, archiveChunkSize)
 				if err != nil {
-					fmt.Println(err)
+					fmt.Printf("Error occurred while creating archive: %v\n", err)
+					return err
 				}
-				err = ocAdmInspect.Run()
-				if err != nil {
-					fmt.Println(err)
+				for _, chunk := range index.Chunks {
+					fmt.Printf("Archive %s (%d files)\n", chunk.Name, len(chunk.Files))
 				}
-				// TODO add entry in markdown for finding things
 			}
+			return errors.Join(errs...)
+			// TODO Should / Can must-gather collect node and node-agent /dev/ and /host_pods files info.
+		},
+	}
+)
 
-			// gather_logs
+// ocAdmInspectFatal is raised by oc adm inspect instead of exiting, so it is returned as error of its cluster
+type ocAdmInspectFatal struct {
+	message string
+}
+
+// gatheredClusters is the context gathering each cluster ID, so contexts of the same cluster do not overwrite each other output
+type gatheredClusters struct {
+	lock     sync.Mutex
+	contexts map[string]string
+}
+
+func (clusters *gatheredClusters) claim(clusterID string, contextText string) error {
+	clusters.lock.Lock()
+	defer clusters.lock.Unlock()
+	if otherContextText, ok := clusters.contexts[clusterID]; ok {
+		return fmt.Errorf("cluster %s is already gathered with %s", clusterID, otherContextText)
+	}
+	clusters.contexts[clusterID] = contextText
+	return nil
+}
+
+func runOcAdmInspect(args []string) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		fatal, ok := recovered.(ocAdmInspectFatal)
+		if !ok {
+			panic(recovered)
+		}
+		err = errors.New(fatal.message)
+	}()
+	// command is used instead of options, so it uses same kubeconfig and context
+	// https://github.com/openshift/oc/blob/ae1bd9e4a75b8ab617a569e5c8e1a0d7285a16f6/pkg/cli/admin/inspect/inspect.go#L101
+	ocAdmInspect := ocadminspect.NewCmdInspect(genericiooptions.NewTestIOStreamsDiscard())
+	ocAdmInspect.SetArgs(args)
+	return ocAdmInspect.Execute()
+}
+
+// gatherCluster gathers cluster of kubeconfig context in clusters/<id> and writes its summary
+func gatherCluster(kubeContext string, clusters *gatheredClusters, supportMatrix *supportmatrix.SupportMatrix, knownIssues *knownissues.Catalog) (*templates.ClusterOverview, error) {
+	contextText := "current context"
+	if len(kubeContext) != 0 {
+		contextText = "context " + kubeContext
+	}
+	clusterConfig, err := gather.ClusterConfig(Kubeconfig, kubeContext)
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while loading cluster config: %v\n", contextText, err)
+		return nil, err
+	}
+	// https://github.com/openshift/oc/blob/46db7c2bce5a57e3c3d9347e7e1e107e61dbd306/pkg/cli/admin/inspect/inspect.go#L142
+	clusterConfig.QPS = 999999
+	clusterConfig.Burst = 999999
+
+	// each cluster has its own scheme, because clusters are gathered concurrently
+	clusterClient, err := client.New(clusterConfig, client.Options{Scheme: runtime.NewScheme()})
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while creating Go client: %v\n", contextText, err)
+		return nil, err
+	}
 
-			// gather_metrics
-			// Find problem with velero metrics (port?) and kill html, add to summary.md file
+	// in what versions of OCP must must-gather work? be careful about API versions update?
+	err = openshiftconfigv1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = operatorsv1alpha1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = operatorsv1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = apiextensionsv1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = appsv1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = storagev1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = batchv1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = volumesnapshotv1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = corev1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	// OADP CRDs
+	err = oadpv1alpha1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = nac1alpha1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = velerov1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
+	err = velerov2alpha1.AddToScheme(clusterClient.Scheme())
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while adding to scheme: %v\n", contextText, err)
+		return nil, err
+	}
 
-			// gather_versions https://github.com/openshift/oadp-operator/pull/994
-			if len(storageClassList.Items) == 0 {
-				fmt.Println(fmt.Errorf("no StorageClass found in cluster"))
-			}
+	clientset, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while creating Go clientset: %v\n", contextText, err)
+		return nil, err
+	}
 
-			if len(volumeSnapshotClassList.Items) == 0 {
-				fmt.Println(fmt.Errorf("no VolumeSnapshotClass found in cluster"))
-			}
+	clusterVersionList := &openshiftconfigv1.ClusterVersionList{}
+	err = gather.AllResources(clusterClient, clusterVersionList)
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s, an error happened while gathering ClusterVersion: %v\n", contextText, err)
+		return nil, err
+	}
+	if len(clusterVersionList.Items) == 0 {
+		err = fmt.Errorf("no ClusterVersion found in cluster")
+		fmt.Printf("Unable to gather cluster of %s, an error happened while gathering ClusterVersion: %v\n", contextText, err)
+		return nil, err
+	}
+	clusterVersion := &clusterVersionList.Items[0]
+	clusterID := string(clusterVersion.Spec.ClusterID[:8])
+	err = clusters.claim(clusterID, contextText)
+	if err != nil {
+		fmt.Printf("Unable to gather cluster of %s: %v\n", contextText, err)
+		return nil, err
+	}
+	// OpenShift version used to check CSI drivers support, latest known one if cluster version is not in support matrix
+	// (cluster version is kept if support matrix has no OpenShift version)
+	oadpOpenShiftVersion := supportmatrix.MinorVersion(clusterVersion.Status.Desired.Version)
+	if _, ok := supportMatrix.OpenShiftRelease(oadpOpenShiftVersion); !ok {
+		if latestOpenShiftVersion := supportMatrix.LatestOpenShiftVersion(); len(latestOpenShiftVersion) != 0 {
+			oadpOpenShiftVersion = latestOpenShiftVersion
+		}
+	}
 
-			if len(csiDriverList.Items) == 0 {
-				fmt.Println(fmt.Errorf("no CSIDriver found in cluster"))
-			}
+	// for now, lest keep the folder structure as it is
+	//     must-gather/clusters/<id>/cluster-scoped-resources/apiextensions.k8s.io/customresourcedefinitions
+	//     must-gather/clusters/<id>/namespaces/<name>/velero.io/<name>
+	//     must-gather/clusters/<id>/namespaces/<name>/oadp.openshift.io/<name>
+	// otherwise may break `omg` usage. ref https://github.com/openshift/oadp-operator/pull/1269
+	outputPath := filepath.Join(DestDir, "clusters", clusterID) + "/"
+
+	manifest.StartStep(outputPath, "gather cluster resources")
+
+	var resourcesToGather []client.ObjectList
+	infrastructureList := &openshiftconfigv1.InfrastructureList{}
+	nodeList := &corev1.NodeList{}
+	clusterServiceVersionList := &operatorsv1alpha1.ClusterServiceVersionList{}
+	// TODO when Velero/OADP API updates, how to handle? use dynamic client instead?
+	dataProtectionApplicationList := &oadpv1alpha1.DataProtectionApplicationList{}
+	cloudStorageList := &oadpv1alpha1.CloudStorageList{}
+	backupStorageLocationList := &velerov1.BackupStorageLocationList{}
+	volumeSnapshotLocationList := &velerov1.VolumeSnapshotLocationList{}
+	backupList := &velerov1.BackupList{}
+	restoreList := &velerov1.RestoreList{}
+	scheduleList := &velerov1.ScheduleList{}
+	backupRepositoryList := &velerov1.BackupRepositoryList{}
+	dataUploadList := &velerov2alpha1.DataUploadList{}
+	dataDownloadList := &velerov2alpha1.DataDownloadList{}
+	podVolumeBackupList := &velerov1.PodVolumeBackupList{}
+	podVolumeRestoreList := &velerov1.PodVolumeRestoreList{}
+	downloadRequestList := &velerov1.DownloadRequestList{}
+	deleteBackupRequestList := &velerov1.DeleteBackupRequestList{}
+	serverStatusRequestList := &velerov1.ServerStatusRequestList{}
+	volumeSnapshotList := &volumesnapshotv1.VolumeSnapshotList{}
+
+	storageClassList := &storagev1.StorageClassList{}
+	volumeSnapshotClassList := &volumesnapshotv1.VolumeSnapshotClassList{}
+	csiDriverList := &storagev1.CSIDriverList{}
+	persistentVolumeClaimList := &corev1.PersistentVolumeClaimList{}
+	customResourceDefinitionList := &apiextensionsv1.CustomResourceDefinitionList{}
+	resourcesToGather = append(resourcesToGather,
+		infrastructureList,
+		nodeList,
+		clusterServiceVersionList,
+		dataProtectionApplicationList,
+		cloudStorageList,
+		backupStorageLocationList,
+		volumeSnapshotLocationList,
+		backupList,
+		restoreList,
+		scheduleList,
+		backupRepositoryList,
+		dataUploadList,
+		dataDownloadList,
+		podVolumeBackupList,
+		podVolumeRestoreList,
+		downloadRequestList,
+		deleteBackupRequestList,
+		serverStatusRequestList,
+		volumeSnapshotList,
+
+		storageClassList,
+		volumeSnapshotClassList,
+		csiDriverList,
+		persistentVolumeClaimList,
+		customResourceDefinitionList,
+	)
+	for _, resource := range resourcesToGather {
+		// TODO  do this part in parallel?
+		err = gather.AllResources(clusterClient, resource)
+		if err != nil {
+			fmt.Println(err)
+			manifest.RecordFailure(outputPath, manifest.APICall(resource), err)
+		}
+	}
 
-			// TODO do processes in parallel!?
-			// https://gobyexample.com/waitgroups
-			// https://github.com/konveyor/analyzer-lsp/blob/main/engine/engine.go
-			templates.ReplaceMustGatherVersion(mustGatherVersion)
-			templates.ReplaceClusterInformationSection(outputPath, clusterID, clusterVersion, infrastructure, nodeList)
-			templates.ReplaceOADPOperatorInstallationSection(outputPath, importantCSVsByNamespace, foundOADP, foundRelatedProducts, oadpOperatorsText)
-			templates.ReplaceDataProtectionApplicationsSection(outputPath, dataProtectionApplicationList)
-			templates.ReplaceCloudStoragesSection(outputPath, cloudStorageList)
-			templates.ReplaceBackupStorageLocationsSection(outputPath, backupStorageLocationList)
-			templates.ReplaceVolumeSnapshotLocationsSection(outputPath, volumeSnapshotLocationList)
-			templates.ReplaceBackupsSection(outputPath, backupList, clusterClient, deleteBackupRequestList, podVolumeBackupList)
-			templates.ReplaceRestoresSection(outputPath, restoreList, clusterClient, podVolumeRestoreList)
-			templates.ReplaceSchedulesSection(outputPath, scheduleList)
-			templates.ReplaceBackupRepositoriesSection(outputPath, backupRepositoryList)
-			templates.ReplaceDataUploadsSection(outputPath, dataUploadList)
-			templates.ReplaceDataDownloadsSection(outputPath, dataDownloadList)
-			templates.ReplacePodVolumeBackupsSection(outputPath, podVolumeBackupList)
-			templates.ReplacePodVolumeRestoresSection(outputPath, podVolumeRestoreList)
-			templates.ReplaceDownloadRequestsSection(outputPath, downloadRequestList)
-			templates.ReplaceDeleteBackupRequestsSection(outputPath, deleteBackupRequestList)
-			templates.ReplaceServerStatusRequestsSection(outputPath, serverStatusRequestList)
-			// TODO NAC CRs
-			templates.ReplaceAvailableStorageClassesSection(outputPath, storageClassList)
-			templates.ReplaceAvailableVolumeSnapshotClassesSection(outputPath, volumeSnapshotClassList)
-			templates.ReplaceAvailableCSIDriversSection(outputPath, csiDriverList, oadpOpenShiftVersion)
-			templates.ReplaceCustomResourceDefinitionsSection(outputPath, clusterConfig)
-			// do not tar!
-			err = templates.Write(outputPath)
+	maintenanceJobList := &batchv1.JobList{}
+	err = gather.AllResourcesWithLabel(clusterClient, maintenanceJobList, repository.RepositoryNameLabel)
+	if err != nil {
+		fmt.Println(err)
+	}
+	maintenancePodList := &corev1.PodList{}
+	err = gather.AllResourcesWithLabel(clusterClient, maintenancePodList, repository.RepositoryNameLabel)
+	if err != nil {
+		fmt.Println(err)
+	}
+	nodeAgentPodList := &corev1.PodList{}
+	err = gather.AllResourcesMatchingLabels(clusterClient, nodeAgentPodList, gather.NodeAgentPodLabels)
+	if err != nil {
+		fmt.Println(err)
+	}
+	veleroDeploymentList := &appsv1.DeploymentList{}
+	err = gather.AllResourcesMatchingLabels(clusterClient, veleroDeploymentList, gather.VeleroDeploymentLabels)
+	if err != nil {
+		fmt.Println(err)
+	}
+	helmVeleroDeploymentList := &appsv1.DeploymentList{}
+	err = gather.AllResourcesMatchingLabels(clusterClient, helmVeleroDeploymentList, gather.HelmVeleroDeploymentLabels)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, deployment := range helmVeleroDeploymentList.Items {
+		if !slices.ContainsFunc(veleroDeploymentList.Items, func(veleroDeployment appsv1.Deployment) bool {
+			return veleroDeployment.UID == deployment.UID
+		}) {
+			veleroDeploymentList.Items = append(veleroDeploymentList.Items, deployment)
+		}
+	}
+	backedUpPodList := &corev1.PodList{}
+	if gather.BackupsIncludeAllNamespaces(backupList) {
+		err = gather.AllResources(clusterClient, backedUpPodList)
+		if err != nil {
+			fmt.Println(err)
+		}
+	} else {
+		for _, namespace := range gather.BackedUpNamespaces(backupList) {
+			namespacePodList := &corev1.PodList{}
+			err = gather.ResourcesInNamespace(clusterClient, namespacePodList, namespace)
 			if err != nil {
-				fmt.Printf("Error occurred: %v\n", err)
-				return err
+				fmt.Println(err)
 			}
-			return nil
-			// TODO Should / Can must-gather collect node and node-agent /dev/ and /host_pods files info.
-		},
+			backedUpPodList.Items = append(backedUpPodList.Items, namespacePodList.Items...)
+		}
 	}
-)
+
+	if len(infrastructureList.Items) == 0 {
+		err = fmt.Errorf("no Infrastructure found in cluster")
+		fmt.Printf("Unable to gather cluster of %s, an error happened while gathering Infrastructure: %v\n", contextText, err)
+		return nil, err
+	}
+	infrastructure := &infrastructureList.Items[0]
+
+	if len(nodeList.Items) == 0 {
+		fmt.Println(fmt.Errorf("no Node found in cluster"))
+	}
+
+	// get namespaces with OADP installs
+
+	if len(clusterServiceVersionList.Items) == 0 {
+		fmt.Println(fmt.Errorf("no ClusterServiceVersion found in cluster"))
+	}
+	oadpOperatorsText := ""
+	foundOADP := false
+	foundRelatedProducts := false
+	foundVirtualization := false
+	foundACM := false
+	importantCSVsByNamespace := map[string][]operatorsv1alpha1.ClusterServiceVersion{}
+
+	// ?Managed Velero operator? only available in ROSA? https://github.com/openshift/managed-velero-operator
+	//
+	// ?IBM Fusion?
+	//
+	// ?Dell Power Protect?
+	//
+	// upstream velero?
+	relatedProducts := []string{"OpenShift Virtualization", "Advanced Cluster Management for Kubernetes", "Submariner"}
+	communityProducts := []string{"KubeVirt HyperConverged Cluster Operator"}
+
+	for _, csv := range clusterServiceVersionList.Items {
+		// OADP dev, community and prod operators have same spec.displayName
+		if csv.Spec.DisplayName == "OADP Operator" {
+			oadpOperatorsText += fmt.Sprintf("Found **%v** version **%v** installed in **%v** namespace\n\n", csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace)
+			foundOADP = true
+			importantCSVsByNamespace[csv.Namespace] = append(importantCSVsByNamespace[csv.Namespace], csv)
+		}
+		if csv.Spec.DisplayName == "OpenShift Virtualization" || csv.Spec.DisplayName == "KubeVirt HyperConverged Cluster Operator" {
+			foundVirtualization = true
+		}
+		if csv.Spec.DisplayName == "Advanced Cluster Management for Kubernetes" {
+			foundACM = true
+		}
+		if slices.Contains(relatedProducts, csv.Spec.DisplayName) {
+			oadpOperatorsText += fmt.Sprintf("Found related product **%v** version **%v** installed in **%v** namespace\n\n", csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace)
+			foundRelatedProducts = true
+			importantCSVsByNamespace[csv.Namespace] = append(importantCSVsByNamespace[csv.Namespace], csv)
+		}
+		if slices.Contains(communityProducts, csv.Spec.DisplayName) {
+			oadpOperatorsText += fmt.Sprintf("⚠️ Found related product **%v (Community)** version **%v** installed in **%v** namespace\n\n", csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace)
+			foundRelatedProducts = true
+			importantCSVsByNamespace[csv.Namespace] = append(importantCSVsByNamespace[csv.Namespace], csv)
+		}
+	}
+
+	subscriptionList := &operatorsv1alpha1.SubscriptionList{}
+	installPlanList := &operatorsv1alpha1.InstallPlanList{}
+	operatorGroupList := &operatorsv1.OperatorGroupList{}
+	for _, namespace := range slices.Sorted(maps.Keys(importantCSVsByNamespace)) {
+		namespaceSubscriptionList := &operatorsv1alpha1.SubscriptionList{}
+		err = gather.ResourcesInNamespace(clusterClient, namespaceSubscriptionList, namespace)
+		if err != nil {
+			fmt.Println(err)
+		}
+		subscriptionList.Items = append(subscriptionList.Items, namespaceSubscriptionList.Items...)
+		namespaceInstallPlanList := &operatorsv1alpha1.InstallPlanList{}
+		err = gather.ResourcesInNamespace(clusterClient, namespaceInstallPlanList, namespace)
+		if err != nil {
+			fmt.Println(err)
+		}
+		installPlanList.Items = append(installPlanList.Items, namespaceInstallPlanList.Items...)
+		namespaceOperatorGroupList := &operatorsv1.OperatorGroupList{}
+		err = gather.ResourcesInNamespace(clusterClient, namespaceOperatorGroupList, namespace)
+		if err != nil {
+			fmt.Println(err)
+		}
+		operatorGroupList.Items = append(operatorGroupList.Items, namespaceOperatorGroupList.Items...)
+	}
+	catalogSourceList := &operatorsv1alpha1.CatalogSourceList{}
+	if len(subscriptionList.Items) != 0 {
+		err = gather.AllResources(clusterClient, catalogSourceList)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	var virtualMachineList, virtualMachineInstanceList, dataVolumeList *unstructured.UnstructuredList
+	virtLauncherPodList := &corev1.PodList{}
+	if foundVirtualization {
+		virtualizationNamespaces := append([]string{}, gather.BackedUpNamespaces(backupList)...)
+		if gather.BackupsIncludeAllNamespaces(backupList) {
+			// nil gathers from all namespaces
+			virtualizationNamespaces = nil
+		}
+		virtualMachineList, err = gather.UnstructuredResources(clusterClient, gvk.VirtualMachineListGVK, virtualizationNamespaces)
+		if err != nil {
+			fmt.Println(err)
+		}
+		virtualMachineInstanceList, err = gather.UnstructuredResources(clusterClient, gvk.VirtualMachineInstanceListGVK, virtualizationNamespaces)
+		if err != nil {
+			fmt.Println(err)
+		}
+		dataVolumeList, err = gather.UnstructuredResources(clusterClient, gvk.DataVolumeListGVK, virtualizationNamespaces)
+		if err != nil {
+			fmt.Println(err)
+		}
+		err = gather.AllResourcesMatchingLabels(clusterClient, virtLauncherPodList, gather.VirtLauncherPodLabels)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	var backupScheduleList, acmRestoreList *unstructured.UnstructuredList
+	if foundACM {
+		backupScheduleList, err = gather.UnstructuredResources(clusterClient, gvk.BackupScheduleListGVK, nil)
+		if err != nil {
+			fmt.Println(err)
+		}
+		acmRestoreList, err = gather.UnstructuredResources(clusterClient, gvk.ACMRestoreListGVK, nil)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	// gather_logs
+
+	// gather_metrics
+	// Find problem with velero metrics (port?) and kill html, add to summary.md file
+
+	// gather_versions https://github.com/openshift/oadp-operator/pull/994
+	if len(storageClassList.Items) == 0 {
+		fmt.Println(fmt.Errorf("no StorageClass found in cluster"))
+	}
+
+	if len(volumeSnapshotClassList.Items) == 0 {
+		fmt.Println(fmt.Errorf("no VolumeSnapshotClass found in cluster"))
+	}
+
+	if len(csiDriverList.Items) == 0 {
+		fmt.Println(fmt.Errorf("no CSIDriver found in cluster"))
+	}
+
+	relationshipIndex := gather.NewRelationshipIndex(
+		dataUploadList,
+		dataDownloadList,
+		podVolumeBackupList,
+		podVolumeRestoreList,
+		volumeSnapshotList,
+		downloadRequestList,
+		backupRepositoryList,
+	)
+
+	// TODO do processes in parallel!?
+	// https://gobyexample.com/waitgroups
+	// https://github.com/konveyor/analyzer-lsp/blob/main/engine/engine.go
+	summary := templates.NewSummary(knownIssues)
+	artifacts := gather.NewArtifacts(clusterClient)
+	manifest.StartStep(outputPath, "summary installation sections")
+	summary.ReplaceMustGatherVersion(mustGatherVersion)
+	summary.ReplaceClusterInformationSection(outputPath, clusterID, clusterVersion, infrastructure, nodeList)
+	summary.ReplaceOADPOperatorInstallationSection(outputPath, importantCSVsByNamespace, foundOADP, foundRelatedProducts, oadpOperatorsText)
+	summary.ReplaceSupportMatrixSection(supportMatrix, importantCSVsByNamespace, clusterVersion, veleroDeploymentList)
+	summary.ReplaceOLMSection(outputPath, importantCSVsByNamespace, subscriptionList, installPlanList, operatorGroupList, catalogSourceList)
+	summary.ReplaceInstallationConflictsSection(clusterServiceVersionList, veleroDeploymentList, customResourceDefinitionList)
+	summary.ReplaceDataProtectionApplicationsSection(outputPath, dataProtectionApplicationList)
+	summary.ReplaceNodeAgentCoverageSection(nodeList, dataProtectionApplicationList, nodeAgentPodList, podVolumeBackupList, backedUpPodList)
+	summary.ReplaceVirtualizationSection(outputPath, foundVirtualization, dataProtectionApplicationList, backupList, virtualMachineList, virtualMachineInstanceList, dataVolumeList, persistentVolumeClaimList, virtLauncherPodList)
+	summary.ReplaceACMSection(outputPath, foundACM, backupScheduleList, acmRestoreList, scheduleList, backupList)
+	manifest.StartStep(outputPath, "summary Backup and Restore sections")
+	summary.ReplaceCloudStoragesSection(outputPath, cloudStorageList)
+	summary.ReplaceBackupStorageLocationsSection(outputPath, backupStorageLocationList)
+	summary.ReplaceVolumeSnapshotLocationsSection(outputPath, volumeSnapshotLocationList)
+	summary.ReplaceBackupsSection(outputPath, backupList, clusterClient, deleteBackupRequestList, podVolumeBackupList, relationshipIndex)
+	summary.ReplaceBackupRetentionSection(backupList, deleteBackupRequestList, dataProtectionApplicationList)
+	summary.ReplaceStorageConsumptionSection(artifacts, backupList, podVolumeBackupList, dataUploadList)
+	summary.ReplaceRestoresSection(outputPath, restoreList, clusterClient, podVolumeRestoreList, relationshipIndex)
+	summary.ReplaceRestoreResultsSection(outputPath, artifacts, restoreList)
+	summary.ReplaceAPIAvailabilitySection(artifacts, clusterConfig, restoreList, backupList, dataProtectionApplicationList)
+	summary.ReplaceSchedulesSection(outputPath, scheduleList)
+	summary.ReplacePerformanceSection(backupList, restoreList, podVolumeBackupList, podVolumeRestoreList, dataUploadList, dataDownloadList)
+	manifest.StartStep(outputPath, "summary Data Mover and File System Backup sections")
+	summary.ReplaceBackupRepositoriesSection(outputPath, backupRepositoryList, maintenanceJobList, maintenancePodList, clientset)
+	summary.ReplaceDataUploadsSection(outputPath, dataUploadList, backupList)
+	summary.ReplaceDataDownloadsSection(outputPath, dataDownloadList)
+	summary.ReplacePodVolumeBackupsSection(outputPath, podVolumeBackupList)
+	summary.ReplacePodVolumeBackupsBreakdownSection(outputPath, podVolumeBackupList, nodeAgentPodList, clusterClient, clientset, LogsSince)
+	summary.ReplacePodVolumeRestoresSection(outputPath, podVolumeRestoreList)
+	summary.ReplacePodVolumeRestoresBreakdownSection(outputPath, podVolumeRestoreList, nodeAgentPodList, clusterClient, clientset, LogsSince)
+	summary.ReplaceVolumeSnapshotsSection(outputPath, volumeSnapshotList)
+	summary.ReplaceDownloadRequestsSection(outputPath, downloadRequestList)
+	summary.ReplaceDeleteBackupRequestsSection(outputPath, deleteBackupRequestList)
+	summary.ReplaceServerStatusRequestsSection(outputPath, serverStatusRequestList)
+	// TODO NAC CRs
+	manifest.StartStep(outputPath, "summary storage sections")
+	summary.ReplaceCSISnapshotMatrixSection(storageClassList, volumeSnapshotClassList, csiDriverList, persistentVolumeClaimList, supportMatrix, oadpOpenShiftVersion)
+	summary.ReplaceAvailableStorageClassesSection(outputPath, storageClassList)
+	summary.ReplaceAvailableVolumeSnapshotClassesSection(outputPath, volumeSnapshotClassList)
+	summary.ReplaceAvailableCSIDriversSection(outputPath, csiDriverList, oadpOpenShiftVersion)
+	summary.ReplaceCustomResourceDefinitionsSection(outputPath, clusterConfig)
+	// after all sections that gather logs
+	manifest.StartStep(outputPath, "summary log analysis")
+	summary.ReplaceTopLogErrorsSection()
+	summary.ReplaceKnownIssuesSection(
+		clusterClient.Scheme(),
+		backupStorageLocationList,
+		backupRepositoryList,
+		dataUploadList,
+		dataDownloadList,
+		backupList,
+		restoreList,
+	)
+	// logs and historical objects last, with what is left of size budget
+	summary.BudgetLogs()
+	manifest.StartStep(outputPath, "oc adm inspect")
+	// oc adm inspect --dest-dir must-gather/clusters/${clusterID} ns/${ns}
+	if len(importantCSVsByNamespace) != 0 {
+		// written to a temporary dir, so it is only kept if it fits in size budget
+		ocAdmInspectDestDir := outputPath + "oc-adm-inspect-tmp/"
+		ocAdmInspectArgs := []string{"--dest-dir", ocAdmInspectDestDir}
+		if len(Kubeconfig) != 0 {
+			ocAdmInspectArgs = append(ocAdmInspectArgs, "--kubeconfig", Kubeconfig)
+		}
+		if len(kubeContext) != 0 {
+			ocAdmInspectArgs = append(ocAdmInspectArgs, "--context", kubeContext)
+		}
+		ocAdmInspectNamespaces := []string{}
+		for namespace := range importantCSVsByNamespace {
+			ocAdmInspectNamespaces = append(ocAdmInspectNamespaces, "ns/"+namespace)
+		}
+
+		err = runOcAdmInspect(append(ocAdmInspectArgs, ocAdmInspectNamespaces...))
+		if err != nil {
+			fmt.Println(err)
+			manifest.RecordFailure(outputPath, "oc adm inspect", err)
+		}
+		err = summary.MoveWithinBudget(ocAdmInspectDestDir, outputPath, "`oc adm inspect` of "+strings.Join(ocAdmInspectNamespaces, ", "))
+		if err != nil {
+			fmt.Println(err)
+			manifest.RecordFailure(outputPath, "oc adm inspect", err)
+		}
+		// TODO add entry in markdown for finding things
+	}
+
+	summary.ReplaceSizeBudgetSection()
+	// do not tar by default, oc adm must-gather handles transfer
+	manifest.StartStep(outputPath, "write summary")
+	err = summary.Write(outputPath)
+	if err != nil {
+		fmt.Printf("Error occurred: %v\n", err)
+		return nil, err
+	}
+
+	overview := &templates.ClusterOverview{
+		Context:                kubeContext,
+		ClusterID:              clusterID,
+		OpenShiftVersion:       clusterVersion.Status.Desired.Version,
+		BackupStorageLocations: backupStorageLocationList.Items,
+		Backups:                backupList.Items,
+	}
+	for _, csv := range clusterServiceVersionList.Items {
+		if csv.Spec.DisplayName == "OADP Operator" {
+			overview.OADPVersions = append(overview.OADPVersions, fmt.Sprintf("%s (%s)", csv.Spec.Version, csv.Namespace))
+		}
+	}
+	return overview, nil
+}
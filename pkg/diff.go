@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/diff"
+)
+
+var DiffCLI = &cobra.Command{
+	Use:   "diff <dirA> <dirB>",
+	Short: "Show what changed between two OADP must-gathers",
+	Long: `Show what changed between two OADP must-gathers
+
+Compares OADP resources, Backup and Restore phases, summary findings and versions
+of OADP must-gather dirA (before) and dirB (after). Does not need cluster access.`,
+	Args: cobra.ExactArgs(2),
+	Example: `  # compare collections before and after a fix
+  /usr/bin/gather diff must-gather-before/ must-gather-after/`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		before, err := diff.Load(args[0])
+		if err != nil {
+			fmt.Printf("Unable to read must-gather %s: %v\n", args[0], err)
+			return err
+		}
+		after, err := diff.Load(args[1])
+		if err != nil {
+			fmt.Printf("Unable to read must-gather %s: %v\n", args[1], err)
+			return err
+		}
+		fmt.Print(diff.Compare(before, after))
+		return nil
+	},
+}
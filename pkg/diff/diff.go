@@ -0,0 +1,371 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/logs"
+)
+
+const summaryFileName = "oadp-must-gather-summary.md"
+
+// API groups of OADP and Velero CustomResources
+var oadpGroups = []string{"oadp.openshift.io", "velero.io"}
+
+// kinds created by must-gather itself or that expire in minutes, so they are not compared
+var ignoredKinds = []string{"DownloadRequest", "ServerStatusRequest"}
+
+type object struct {
+	// ID of cluster the object was gathered from, empty if unknown
+	cluster    string
+	apiVersion string
+	kind       string
+	namespace  string
+	name       string
+	content    map[string]interface{}
+}
+
+func (o *object) key() string {
+	key := o.kind + " " + o.name
+	if len(o.namespace) != 0 {
+		key = fmt.Sprintf("%s %s/%s", o.kind, o.namespace, o.name)
+	}
+	return clusterPrefix(o.cluster) + key
+}
+
+// clusterPrefix returns prefix of keys and findings of a cluster, so clusters of a multi-cluster must-gather are compared separately
+func clusterPrefix(cluster string) string {
+	if len(cluster) == 0 {
+		return ""
+	}
+	return "cluster " + cluster + ": "
+}
+
+// clusterOf returns cluster ID of a path of must-gather dir, from its clusters/<id>/ folder
+func clusterOf(dir string, path string) string {
+	relativePath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(relativePath), "/")
+	for index := 0; index < len(parts)-2; index++ {
+		if parts[index] == "clusters" {
+			return parts[index+1]
+		}
+	}
+	return ""
+}
+
+func (o *object) group() string {
+	group, _, found := strings.Cut(o.apiVersion, "/")
+	if !found {
+		return ""
+	}
+	return group
+}
+
+func (o *object) field(path ...string) interface{} {
+	var current interface{} = o.content
+	for _, key := range path {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = currentMap[key]
+	}
+	return current
+}
+
+func (o *object) stringField(path ...string) string {
+	value, _ := o.field(path...).(string)
+	return value
+}
+
+// Collection is the content of a must-gather directory
+type Collection struct {
+	// cluster <id>: <kind> <namespace>/<name> : object
+	objects  map[string]*object
+	findings []string
+}
+
+// Load reads YAML files and summary findings of a must-gather directory
+func Load(dir string) (*Collection, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	collection := &Collection{objects: map[string]*object{}}
+	foundSummary := false
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		switch {
+		case entry.Name() == summaryFileName:
+			foundSummary = true
+			findings, err := summaryFindings(path)
+			if err != nil {
+				return err
+			}
+			for _, finding := range findings {
+				collection.findings = append(collection.findings, clusterPrefix(clusterOf(dir, path))+finding)
+			}
+		case strings.HasSuffix(entry.Name(), ".yaml"):
+			collection.addYAML(path, clusterOf(dir, path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !foundSummary {
+		return nil, fmt.Errorf("no %s found in %s, is it an OADP must-gather directory?", summaryFileName, dir)
+	}
+	return collection, nil
+}
+
+func (collection *Collection) addYAML(path string, cluster string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	content := map[string]interface{}{}
+	// files that are not Kubernetes objects are ignored
+	if yaml.Unmarshal(data, &content) != nil {
+		return
+	}
+	if strings.HasSuffix(fmt.Sprint(content["kind"]), "List") {
+		items, _ := content["items"].([]interface{})
+		for _, item := range items {
+			if itemContent, ok := item.(map[string]interface{}); ok {
+				collection.addObject(itemContent, cluster)
+			}
+		}
+		return
+	}
+	collection.addObject(content, cluster)
+}
+
+func (collection *Collection) addObject(content map[string]interface{}, cluster string) {
+	o := &object{cluster: cluster, content: content}
+	o.apiVersion, _ = content["apiVersion"].(string)
+	o.kind, _ = content["kind"].(string)
+	o.namespace = o.stringField("metadata", "namespace")
+	o.name = o.stringField("metadata", "name")
+	if len(o.kind) == 0 || len(o.name) == 0 {
+		return
+	}
+	collection.objects[o.key()] = o
+}
+
+// changedFindings returns findings that are not in other findings, as a Markdown list.
+// Findings are compared by signature, so numbers, timestamps and quoted values that change between runs (elapsed times, counts)
+// do not make them different. Object names in **name** text are not normalized (only numbers in them), so other names make them different
+func changedFindings(findings []string, otherFindings []string) string {
+	otherSignatures := map[string]bool{}
+	for _, finding := range otherFindings {
+		otherSignatures[logs.Signature(finding)] = true
+	}
+	changed := ""
+	listed := map[string]bool{}
+	for _, finding := range findings {
+		signature := logs.Signature(finding)
+		if otherSignatures[signature] || listed[signature] {
+			continue
+		}
+		listed[signature] = true
+		changed += "- " + finding + "\n"
+	}
+	return changed
+}
+
+// summaryFindings returns lines of summary errors section
+func summaryFindings(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var findings []string
+	inErrors := false
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "## ") {
+			inErrors = line == "## Errors"
+			continue
+		}
+		if !inErrors || len(line) == 0 || strings.HasPrefix(line, "```") || strings.HasPrefix(line, "No errors happened") {
+			continue
+		}
+		// only finding lines, not their evidence or remediation lines
+		if strings.HasPrefix(line, "❌") || strings.HasPrefix(line, "⚠️") || strings.HasPrefix(line, "🚫") || strings.HasPrefix(line, "🔎") {
+			findings = append(findings, line)
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// flatten returns <path>=<value> lines of a value
+func flatten(prefix string, value interface{}, lines map[string]string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			flatten(prefix+"."+key, typed[key], lines)
+		}
+	case []interface{}:
+		for index, item := range typed {
+			flatten(fmt.Sprintf("%s[%d]", prefix, index), item, lines)
+		}
+	default:
+		lines[prefix] = fmt.Sprint(value)
+	}
+}
+
+// specDiff returns changed spec fields between two objects
+func specDiff(before *object, after *object) []string {
+	beforeLines := map[string]string{}
+	afterLines := map[string]string{}
+	flatten("spec", before.field("spec"), beforeLines)
+	flatten("spec", after.field("spec"), afterLines)
+	var changes []string
+	keys := slices.Concat(slices.Collect(maps.Keys(beforeLines)), slices.Collect(maps.Keys(afterLines)))
+	slices.Sort(keys)
+	for _, key := range slices.Compact(keys) {
+		beforeValue, inBefore := beforeLines[key]
+		afterValue, inAfter := afterLines[key]
+		switch {
+		case !inBefore:
+			changes = append(changes, fmt.Sprintf("+ %s: %s", key, afterValue))
+		case !inAfter:
+			changes = append(changes, fmt.Sprintf("- %s: %s", key, beforeValue))
+		case beforeValue != afterValue:
+			changes = append(changes, fmt.Sprintf("~ %s: %s -> %s", key, beforeValue, afterValue))
+		}
+	}
+	return changes
+}
+
+func (collection *Collection) keysOfKind(kinds ...string) []string {
+	var keys []string
+	for key, o := range collection.objects {
+		if slices.Contains(kinds, o.kind) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (collection *Collection) oadpKeys() []string {
+	var keys []string
+	for key, o := range collection.objects {
+		if slices.Contains(oadpGroups, o.group()) && !slices.Contains(ignoredKinds, o.kind) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func sortedUnion(a []string, b []string) []string {
+	keys := slices.Concat(a, b)
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// Compare returns markdown report of what changed from before to after collection
+func Compare(before *Collection, after *Collection) string {
+	report := "# OADP must-gather diff\n\n## Versions\n\n"
+	versionChanges := ""
+	for _, key := range sortedUnion(before.keysOfKind("ClusterVersion"), after.keysOfKind("ClusterVersion")) {
+		beforeVersion, afterVersion := "-", "-"
+		cluster := ""
+		if o, ok := before.objects[key]; ok {
+			beforeVersion = o.stringField("status", "desired", "version")
+			cluster = o.cluster
+		}
+		if o, ok := after.objects[key]; ok {
+			afterVersion = o.stringField("status", "desired", "version")
+			cluster = o.cluster
+		}
+		if beforeVersion != afterVersion {
+			versionChanges += fmt.Sprintf("- %sOpenShift version changed from **%s** to **%s**\n", clusterPrefix(cluster), beforeVersion, afterVersion)
+		}
+	}
+	for _, key := range sortedUnion(before.keysOfKind("ClusterServiceVersion"), after.keysOfKind("ClusterServiceVersion")) {
+		beforeCSV, inBefore := before.objects[key]
+		afterCSV, inAfter := after.objects[key]
+		switch {
+		case !inBefore:
+			versionChanges += fmt.Sprintf("- New %s version **%s**\n", key, afterCSV.stringField("spec", "version"))
+		case !inAfter:
+			versionChanges += fmt.Sprintf("- Removed %s version **%s**\n", key, beforeCSV.stringField("spec", "version"))
+		case beforeCSV.stringField("spec", "version") != afterCSV.stringField("spec", "version"):
+			versionChanges += fmt.Sprintf(
+				"- %s version changed from **%s** to **%s**\n",
+				key, beforeCSV.stringField("spec", "version"), afterCSV.stringField("spec", "version"),
+			)
+		}
+	}
+	if len(versionChanges) == 0 {
+		versionChanges = "No version changes\n"
+	}
+	report += versionChanges + "\n## OADP resources\n\n"
+
+	resourceChanges := ""
+	phaseChanges := ""
+	for _, key := range sortedUnion(before.oadpKeys(), after.oadpKeys()) {
+		beforeObject, inBefore := before.objects[key]
+		afterObject, inAfter := after.objects[key]
+		switch {
+		case !inBefore:
+			resourceChanges += fmt.Sprintf("- ➕ New %s\n", key)
+		case !inAfter:
+			resourceChanges += fmt.Sprintf("- ➖ Removed %s\n", key)
+		default:
+			if !reflect.DeepEqual(beforeObject.field("spec"), afterObject.field("spec")) {
+				resourceChanges += fmt.Sprintf("- ✏️ Changed %s spec\n", key)
+				if afterObject.kind == "DataProtectionApplication" {
+					resourceChanges += fmt.Sprintf("\n```diff\n%s\n```\n\n", strings.Join(specDiff(beforeObject, afterObject), "\n"))
+				}
+			}
+			if afterObject.kind == "Backup" || afterObject.kind == "Restore" {
+				beforePhase := beforeObject.stringField("status", "phase")
+				afterPhase := afterObject.stringField("status", "phase")
+				if beforePhase != afterPhase {
+					phaseChanges += fmt.Sprintf("- %s phase changed from **%s** to **%s**\n", key, beforePhase, afterPhase)
+				}
+			}
+		}
+	}
+	if len(resourceChanges) == 0 {
+		resourceChanges = "No OADP resource changes\n"
+	}
+	if len(phaseChanges) == 0 {
+		phaseChanges = "No Backup or Restore phase changes\n"
+	}
+	report += resourceChanges + "\n## Backup and Restore phases\n\n" + phaseChanges + "\n## Findings\n\n### New findings\n\n"
+
+	newFindings := changedFindings(after.findings, before.findings)
+	resolvedFindings := changedFindings(before.findings, after.findings)
+	if len(newFindings) == 0 {
+		newFindings = "No new findings\n"
+	}
+	if len(resolvedFindings) == 0 {
+		resolvedFindings = "No resolved findings\n"
+	}
+	return report + newFindings + "\n### Resolved findings\n\n" + resolvedFindings
+}
@@ -0,0 +1,45 @@
+package gather
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"github.com/vmware-tanzu/velero/pkg/cmd/util/downloadrequest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Artifacts downloads Backup and Restore artifacts from object storage. Each download creates a DownloadRequest,
+// so every artifact is downloaded once and shared by all sections that need it
+type Artifacts struct {
+	clusterClient client.Client
+	lock          sync.Mutex
+	// <kind> <namespace>/<name> : artifact
+	downloaded map[string]*artifact
+}
+
+type artifact struct {
+	content []byte
+	err     error
+}
+
+func NewArtifacts(clusterClient client.Client) *Artifacts {
+	return &Artifacts{clusterClient: clusterClient, downloaded: map[string]*artifact{}}
+}
+
+// Download returns an artifact of a Backup or Restore, downloading it if it was not downloaded before
+func (artifacts *Artifacts) Download(namespace string, name string, kind velerov1.DownloadTargetKind) ([]byte, error) {
+	artifacts.lock.Lock()
+	defer artifacts.lock.Unlock()
+	key := fmt.Sprintf("%s %s/%s", kind, namespace, name)
+	if downloaded, ok := artifacts.downloaded[key]; ok {
+		return downloaded.content, downloaded.err
+	}
+	writeTo := &bytes.Buffer{}
+	err := downloadrequest.Stream(context.Background(), artifacts.clusterClient, namespace, name, kind, writeTo, 5*time.Second, false, "")
+	artifacts.downloaded[key] = &artifact{content: writeTo.Bytes(), err: err}
+	return writeTo.Bytes(), err
+}
@@ -0,0 +1,24 @@
+package gather
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ClusterConfig returns config of kubeconfig context. If kubeconfig is empty, uses KUBECONFIG environment variable,
+// ~/.kube/config or in cluster config (like in oc adm must-gather pod). If kubeContext is empty, uses current context
+func ClusterConfig(kubeconfig string, kubeContext string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	clusterConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, fmt.Errorf("no cluster config found, use --kubeconfig flag, KUBECONFIG environment variable or ~/.kube/config file")
+		}
+		return nil, err
+	}
+	return clusterConfig, nil
+}
@@ -2,10 +2,144 @@ package gather
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var NodeAgentPodLabels = map[string]string{
+	"component": "velero",
+	"name":      "node-agent",
+}
+
+var VeleroDeploymentLabels = map[string]string{
+	"component": "velero",
+	"deploy":    "velero",
+}
+
+var VirtLauncherPodLabels = map[string]string{
+	"kubevirt.io": "virt-launcher",
+}
+
+// Velero Helm chart does not use the same labels as OADP and velero install
+var HelmVeleroDeploymentLabels = map[string]string{
+	"app.kubernetes.io/name": "velero",
+}
+
 func AllResources(clusterClient client.Client, clusterResource client.ObjectList) error {
 	return clusterClient.List(context.Background(), clusterResource)
 }
+
+func AllResourcesWithLabel(clusterClient client.Client, clusterResource client.ObjectList, labelKey string) error {
+	return clusterClient.List(context.Background(), clusterResource, client.HasLabels{labelKey})
+}
+
+func AllResourcesMatchingLabels(clusterClient client.Client, clusterResource client.ObjectList, labels map[string]string) error {
+	return clusterClient.List(context.Background(), clusterResource, client.MatchingLabels(labels))
+}
+
+func ResourcesInNamespace(clusterClient client.Client, clusterResource client.ObjectList, namespace string) error {
+	return clusterClient.List(context.Background(), clusterResource, client.InNamespace(namespace))
+}
+
+// BackedUpNamespaces returns namespaces explicitly included by Backups
+func BackedUpNamespaces(backupList *velerov1.BackupList) []string {
+	var namespaces []string
+	for _, backup := range backupList.Items {
+		for _, namespace := range backup.Spec.IncludedNamespaces {
+			if namespace != "*" && len(namespace) != 0 && !slices.Contains(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	return namespaces
+}
+
+// BackupsIncludeAllNamespaces returns true if any Backup does not restrict included namespaces
+func BackupsIncludeAllNamespaces(backupList *velerov1.BackupList) bool {
+	for _, backup := range backupList.Items {
+		if len(backup.Spec.IncludedNamespaces) == 0 || slices.Contains(backup.Spec.IncludedNamespaces, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// BackupIncludesNamespace returns true if Backup includes resources of namespace
+func BackupIncludesNamespace(backup *velerov1.Backup, namespace string) bool {
+	if slices.Contains(backup.Spec.ExcludedNamespaces, namespace) {
+		return false
+	}
+	return len(backup.Spec.IncludedNamespaces) == 0 ||
+		slices.Contains(backup.Spec.IncludedNamespaces, "*") ||
+		slices.Contains(backup.Spec.IncludedNamespaces, namespace)
+}
+
+// RestoreIncludesNamespace returns true if Restore restores resources in namespace, including namespaces mapped by spec.namespaceMapping
+func RestoreIncludesNamespace(restore *velerov1.Restore, namespace string) bool {
+	includes := func(sourceNamespace string) bool {
+		if slices.Contains(restore.Spec.ExcludedNamespaces, sourceNamespace) {
+			return false
+		}
+		return len(restore.Spec.IncludedNamespaces) == 0 ||
+			slices.Contains(restore.Spec.IncludedNamespaces, "*") ||
+			slices.Contains(restore.Spec.IncludedNamespaces, sourceNamespace)
+	}
+	for sourceNamespace, targetNamespace := range restore.Spec.NamespaceMapping {
+		if targetNamespace == namespace && includes(sourceNamespace) {
+			return true
+		}
+	}
+	if _, mapped := restore.Spec.NamespaceMapping[namespace]; mapped {
+		return false
+	}
+	return includes(namespace)
+}
+
+// StorageLocationTarget returns provider, bucket and prefix a BackupStorageLocation points to
+func StorageLocationTarget(bsl *velerov1.BackupStorageLocation) string {
+	if bsl.Spec.ObjectStorage == nil {
+		return bsl.Spec.Provider
+	}
+	return fmt.Sprintf("%s %s/%s", bsl.Spec.Provider, bsl.Spec.ObjectStorage.Bucket, bsl.Spec.ObjectStorage.Prefix)
+}
+
+// UnstructuredResources returns resources of a kind without its Go types, from all namespaces if namespaces is nil
+func UnstructuredResources(clusterClient client.Client, listGVK schema.GroupVersionKind, namespaces []string) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(listGVK)
+	if namespaces == nil {
+		return list, AllResources(clusterClient, list)
+	}
+	for _, namespace := range namespaces {
+		namespaceList := &unstructured.UnstructuredList{}
+		namespaceList.SetGroupVersionKind(listGVK)
+		err := ResourcesInNamespace(clusterClient, namespaceList, namespace)
+		if err != nil {
+			return list, err
+		}
+		list.Items = append(list.Items, namespaceList.Items...)
+	}
+	return list, nil
+}
+
+// PodLogs returns container logs of a pod, only since logsSince if it is not zero
+func PodLogs(clientset kubernetes.Interface, pod *corev1.Pod, container string, logsSince time.Duration) (string, error) {
+	options := &corev1.PodLogOptions{Container: container}
+	if logsSince != 0 {
+		sinceSeconds := int64(logsSince.Seconds())
+		options.SinceSeconds = &sinceSeconds
+	}
+	logs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
@@ -0,0 +1,238 @@
+package gather
+
+import (
+	"fmt"
+	"slices"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	velerov2alpha1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v2alpha1"
+	"github.com/vmware-tanzu/velero/pkg/label"
+)
+
+const (
+	ChildSeverityOK      = 0
+	ChildSeverityWarning = 1
+	ChildSeverityError   = 2
+)
+
+var (
+	childOKPhases    = []string{"Completed", "Processed", "Ready"}
+	childErrorPhases = []string{"Failed", "PartiallyFailed", "Canceling", "Canceled", "Error"}
+)
+
+// Child is an object created by Velero on behalf of a Backup or Restore
+type Child struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Phase     string
+}
+
+// Severity ranks the child phase, so the worst child of a Backup or Restore can be found
+func (child Child) Severity() int {
+	if slices.Contains(childOKPhases, child.Phase) {
+		return ChildSeverityOK
+	}
+	if slices.Contains(childErrorPhases, child.Phase) {
+		return ChildSeverityError
+	}
+	// VolumeSnapshots are NotReady while CSI driver takes them, but BackupRepositories are NotReady when they can not be used
+	if child.Kind == "BackupRepository" && child.Phase == string(velerov1.BackupRepositoryPhaseNotReady) {
+		return ChildSeverityError
+	}
+	return ChildSeverityWarning
+}
+
+// RelationshipIndex maps each Backup and Restore to the objects created for it
+type RelationshipIndex struct {
+	// <namespace>/<label valid name> : children
+	backupChildren  map[string][]Child
+	restoreChildren map[string][]Child
+	// VolumeSnapshots live in application namespaces, so they are keyed by <label valid name> and matched by namespaces of Backup or Restore
+	backupVolumeSnapshots  map[string][]Child
+	restoreVolumeSnapshots map[string][]Child
+}
+
+func relationshipKey(namespace string, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, label.GetValidName(name))
+}
+
+func volumeSnapshotPhase(volumeSnapshot volumesnapshotv1.VolumeSnapshot) string {
+	if volumeSnapshot.Status == nil {
+		return ""
+	}
+	if volumeSnapshot.Status.Error != nil {
+		return "Error"
+	}
+	if volumeSnapshot.Status.ReadyToUse != nil && *volumeSnapshot.Status.ReadyToUse {
+		return "Ready"
+	}
+	return "NotReady"
+}
+
+func NewRelationshipIndex(
+	dataUploadList *velerov2alpha1.DataUploadList,
+	dataDownloadList *velerov2alpha1.DataDownloadList,
+	podVolumeBackupList *velerov1.PodVolumeBackupList,
+	podVolumeRestoreList *velerov1.PodVolumeRestoreList,
+	volumeSnapshotList *volumesnapshotv1.VolumeSnapshotList,
+	downloadRequestList *velerov1.DownloadRequestList,
+	backupRepositoryList *velerov1.BackupRepositoryList,
+) *RelationshipIndex {
+	index := &RelationshipIndex{
+		backupChildren:         map[string][]Child{},
+		restoreChildren:        map[string][]Child{},
+		backupVolumeSnapshots:  map[string][]Child{},
+		restoreVolumeSnapshots: map[string][]Child{},
+	}
+	// <namespace>/<label valid name> : BSL : volume namespaces, to find BackupRepositories
+	backupRepositoryKeys := map[string]map[string][]string{}
+	restoreRepositoryKeys := map[string]map[string][]string{}
+	addRepositoryKey := func(keys map[string]map[string][]string, key string, storageLocation string, volumeNamespace string) {
+		if keys[key] == nil {
+			keys[key] = map[string][]string{}
+		}
+		if !slices.Contains(keys[key][storageLocation], volumeNamespace) {
+			keys[key][storageLocation] = append(keys[key][storageLocation], volumeNamespace)
+		}
+	}
+
+	if dataUploadList != nil {
+		for _, dataUpload := range dataUploadList.Items {
+			backupName, ok := dataUpload.Labels[velerov1.BackupNameLabel]
+			if !ok {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", dataUpload.Namespace, backupName)
+			index.backupChildren[key] = append(index.backupChildren[key], Child{
+				Kind: "DataUpload", Namespace: dataUpload.Namespace, Name: dataUpload.Name, Phase: string(dataUpload.Status.Phase),
+			})
+			addRepositoryKey(backupRepositoryKeys, key, dataUpload.Spec.BackupStorageLocation, dataUpload.Spec.SourceNamespace)
+		}
+	}
+	if dataDownloadList != nil {
+		for _, dataDownload := range dataDownloadList.Items {
+			restoreName, ok := dataDownload.Labels[velerov1.RestoreNameLabel]
+			if !ok {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", dataDownload.Namespace, restoreName)
+			index.restoreChildren[key] = append(index.restoreChildren[key], Child{
+				Kind: "DataDownload", Namespace: dataDownload.Namespace, Name: dataDownload.Name, Phase: string(dataDownload.Status.Phase),
+			})
+			addRepositoryKey(restoreRepositoryKeys, key, dataDownload.Spec.BackupStorageLocation, dataDownload.Spec.SourceNamespace)
+		}
+	}
+	if podVolumeBackupList != nil {
+		for _, podVolumeBackup := range podVolumeBackupList.Items {
+			backupName, ok := podVolumeBackup.Labels[velerov1.BackupNameLabel]
+			if !ok {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", podVolumeBackup.Namespace, backupName)
+			index.backupChildren[key] = append(index.backupChildren[key], Child{
+				Kind: "PodVolumeBackup", Namespace: podVolumeBackup.Namespace, Name: podVolumeBackup.Name, Phase: string(podVolumeBackup.Status.Phase),
+			})
+			addRepositoryKey(backupRepositoryKeys, key, podVolumeBackup.Spec.BackupStorageLocation, podVolumeBackup.Spec.Pod.Namespace)
+		}
+	}
+	if podVolumeRestoreList != nil {
+		for _, podVolumeRestore := range podVolumeRestoreList.Items {
+			restoreName, ok := podVolumeRestore.Labels[velerov1.RestoreNameLabel]
+			if !ok {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", podVolumeRestore.Namespace, restoreName)
+			index.restoreChildren[key] = append(index.restoreChildren[key], Child{
+				Kind: "PodVolumeRestore", Namespace: podVolumeRestore.Namespace, Name: podVolumeRestore.Name, Phase: string(podVolumeRestore.Status.Phase),
+			})
+			addRepositoryKey(restoreRepositoryKeys, key, podVolumeRestore.Spec.BackupStorageLocation, podVolumeRestore.Spec.SourceNamespace)
+		}
+	}
+	if volumeSnapshotList != nil {
+		for _, volumeSnapshot := range volumeSnapshotList.Items {
+			child := Child{
+				Kind: "VolumeSnapshot", Namespace: volumeSnapshot.Namespace, Name: volumeSnapshot.Name, Phase: volumeSnapshotPhase(volumeSnapshot),
+			}
+			if backupName, ok := volumeSnapshot.Labels[velerov1.BackupNameLabel]; ok {
+				index.backupVolumeSnapshots[backupName] = append(index.backupVolumeSnapshots[backupName], child)
+			}
+			if restoreName, ok := volumeSnapshot.Labels[velerov1.RestoreNameLabel]; ok {
+				index.restoreVolumeSnapshots[restoreName] = append(index.restoreVolumeSnapshots[restoreName], child)
+			}
+		}
+	}
+	if downloadRequestList != nil {
+		restoreTargets := []velerov1.DownloadTargetKind{
+			velerov1.DownloadTargetKindRestoreLog,
+			velerov1.DownloadTargetKindRestoreResults,
+			velerov1.DownloadTargetKindRestoreResourceList,
+			velerov1.DownloadTargetKindRestoreItemOperations,
+			velerov1.DownloadTargetKindRestoreVolumeInfo,
+		}
+		for _, downloadRequest := range downloadRequestList.Items {
+			key := relationshipKey(downloadRequest.Namespace, downloadRequest.Spec.Target.Name)
+			child := Child{
+				Kind: "DownloadRequest", Namespace: downloadRequest.Namespace, Name: downloadRequest.Name, Phase: string(downloadRequest.Status.Phase),
+			}
+			if slices.Contains(restoreTargets, downloadRequest.Spec.Target.Kind) {
+				index.restoreChildren[key] = append(index.restoreChildren[key], child)
+			} else {
+				index.backupChildren[key] = append(index.backupChildren[key], child)
+			}
+		}
+	}
+	if backupRepositoryList != nil {
+		addBackupRepositories := func(children map[string][]Child, repositoryKeys map[string]map[string][]string) {
+			for key, volumeNamespacesByStorageLocation := range repositoryKeys {
+				for _, backupRepository := range backupRepositoryList.Items {
+					volumeNamespaces := volumeNamespacesByStorageLocation[backupRepository.Spec.BackupStorageLocation]
+					if slices.Contains(volumeNamespaces, backupRepository.Spec.VolumeNamespace) {
+						children[key] = append(children[key], Child{
+							Kind: "BackupRepository", Namespace: backupRepository.Namespace, Name: backupRepository.Name, Phase: string(backupRepository.Status.Phase),
+						})
+					}
+				}
+			}
+		}
+		addBackupRepositories(index.backupChildren, backupRepositoryKeys)
+		addBackupRepositories(index.restoreChildren, restoreRepositoryKeys)
+	}
+
+	return index
+}
+
+func (index *RelationshipIndex) BackupChildren(backup *velerov1.Backup) []Child {
+	children := slices.Clone(index.backupChildren[relationshipKey(backup.Namespace, backup.Name)])
+	for _, volumeSnapshot := range index.backupVolumeSnapshots[label.GetValidName(backup.Name)] {
+		if BackupIncludesNamespace(backup, volumeSnapshot.Namespace) {
+			children = append(children, volumeSnapshot)
+		}
+	}
+	return children
+}
+
+func (index *RelationshipIndex) RestoreChildren(restore *velerov1.Restore) []Child {
+	children := slices.Clone(index.restoreChildren[relationshipKey(restore.Namespace, restore.Name)])
+	for _, volumeSnapshot := range index.restoreVolumeSnapshots[label.GetValidName(restore.Name)] {
+		if RestoreIncludesNamespace(restore, volumeSnapshot.Namespace) {
+			children = append(children, volumeSnapshot)
+		}
+	}
+	return children
+}
+
+// WorstChild returns the child with highest severity and false if there are no children
+func WorstChild(children []Child) (Child, bool) {
+	if len(children) == 0 {
+		return Child{}, false
+	}
+	worst := children[0]
+	for _, child := range children[1:] {
+		if child.Severity() > worst.Severity() {
+			worst = child
+		}
+	}
+	return worst, true
+}
@@ -0,0 +1,67 @@
+package gather
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// BackupResourceList returns resources of a Backup, by <group>/<version>/<kind> (or <version>/<kind> for core group)
+func BackupResourceList(artifacts *Artifacts, backup *velerov1.Backup) (map[string][]string, error) {
+	content, err := artifacts.Download(backup.Namespace, backup.Name, velerov1.DownloadTargetKindBackupResourceList)
+	if err != nil {
+		return nil, err
+	}
+	resourceList := map[string][]string{}
+	err = json.Unmarshal(content, &resourceList)
+	return resourceList, err
+}
+
+// ResourceListGroupVersionKind parses a key of BackupResourceList
+func ResourceListGroupVersionKind(key string) (schema.GroupVersionKind, error) {
+	index := strings.LastIndex(key, "/")
+	if index == -1 {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid resource list key '%s'", key)
+	}
+	groupVersion, err := schema.ParseGroupVersion(key[:index])
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return groupVersion.WithKind(key[index+1:]), nil
+}
+
+// ServedKinds returns kinds served by cluster API, by group version.
+// If some API groups could not be discovered, returns the ones that were and the error
+func ServedKinds(discoveryClient discovery.DiscoveryInterface) (map[schema.GroupVersion][]string, error) {
+	_, resourceLists, err := discoveryClient.ServerGroupsAndResources()
+	served := map[schema.GroupVersion][]string{}
+	for _, resourceList := range resourceLists {
+		groupVersion, parseErr := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if parseErr != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			// subresources, like pods/log
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			served[groupVersion] = append(served[groupVersion], resource.Kind)
+		}
+	}
+	return served, err
+}
+
+// ServedVersions returns versions of an API group served by cluster
+func ServedVersions(served map[schema.GroupVersion][]string, group string) []string {
+	var versions []string
+	for groupVersion := range served {
+		if groupVersion.Group == group {
+			versions = append(versions, groupVersion.Version)
+		}
+	}
+	return versions
+}
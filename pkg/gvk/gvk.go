@@ -15,11 +15,36 @@ var (
 		Version: "v1",
 		Kind:    "List",
 	}
+	JobGVK = schema.GroupVersionKind{
+		Group:   "batch",
+		Version: "v1",
+		Kind:    "Job",
+	}
 	ClusterServiceVersionGVK = schema.GroupVersionKind{
 		Group:   "operators.coreos.com",
 		Version: "v1alpha1",
 		Kind:    "ClusterServiceVersion",
 	}
+	SubscriptionGVK = schema.GroupVersionKind{
+		Group:   "operators.coreos.com",
+		Version: "v1alpha1",
+		Kind:    "Subscription",
+	}
+	InstallPlanGVK = schema.GroupVersionKind{
+		Group:   "operators.coreos.com",
+		Version: "v1alpha1",
+		Kind:    "InstallPlan",
+	}
+	CatalogSourceGVK = schema.GroupVersionKind{
+		Group:   "operators.coreos.com",
+		Version: "v1alpha1",
+		Kind:    "CatalogSource",
+	}
+	OperatorGroupGVK = schema.GroupVersionKind{
+		Group:   "operators.coreos.com",
+		Version: "v1",
+		Kind:    "OperatorGroup",
+	}
 	DataProtectionApplicationGVK = schema.GroupVersionKind{
 		Group:   "oadp.openshift.io",
 		Version: "v1alpha1",
@@ -106,9 +131,39 @@ var (
 		Version: "v1",
 		Kind:    "VolumeSnapshotClass",
 	}
+	VolumeSnapshotGVK = schema.GroupVersionKind{
+		Group:   "snapshot.storage.k8s.io",
+		Version: "v1",
+		Kind:    "VolumeSnapshot",
+	}
 	CSIDriverGVK = schema.GroupVersionKind{
 		Group:   "storage.k8s.io",
 		Version: "v1",
 		Kind:    "CSIDriver",
 	}
+	VirtualMachineListGVK = schema.GroupVersionKind{
+		Group:   "kubevirt.io",
+		Version: "v1",
+		Kind:    "VirtualMachineList",
+	}
+	VirtualMachineInstanceListGVK = schema.GroupVersionKind{
+		Group:   "kubevirt.io",
+		Version: "v1",
+		Kind:    "VirtualMachineInstanceList",
+	}
+	DataVolumeListGVK = schema.GroupVersionKind{
+		Group:   "cdi.kubevirt.io",
+		Version: "v1beta1",
+		Kind:    "DataVolumeList",
+	}
+	BackupScheduleListGVK = schema.GroupVersionKind{
+		Group:   "cluster.open-cluster-management.io",
+		Version: "v1beta1",
+		Kind:    "BackupScheduleList",
+	}
+	ACMRestoreListGVK = schema.GroupVersionKind{
+		Group:   "cluster.open-cluster-management.io",
+		Version: "v1beta1",
+		Kind:    "RestoreList",
+	}
 )
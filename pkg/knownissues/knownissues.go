@@ -0,0 +1,139 @@
+package knownissues
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// maximum evidence lines kept per issue
+const evidenceMax = 5
+
+//go:embed known-issues.yaml
+var embeddedKnownIssues []byte
+
+// issueLinkRegex matches GitHub issues and pull requests, Jira issues and Red Hat Knowledgebase solutions
+var issueLinkRegex = regexp.MustCompile(
+	`^https://(github\.com/[^/]+/[^/]+/(issues|pull)/[0-9]+|issues\.redhat\.com/browse/[A-Z]+-[0-9]+|access\.redhat\.com/solutions/[0-9]+)$`,
+)
+
+type Issue struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Link          string   `json:"link"`
+	Remediation   string   `json:"remediation"`
+	LogPattern    string   `json:"logPattern,omitempty"`
+	StatusKinds   []string `json:"statusKinds,omitempty"`
+	StatusPattern string   `json:"statusPattern,omitempty"`
+
+	logRegex    *regexp.Regexp
+	statusRegex *regexp.Regexp
+}
+
+type Catalog struct {
+	Issues []Issue `json:"issues"`
+}
+
+// Hit is an issue found during must-gather, with lines that show it
+type Hit struct {
+	Issue    *Issue
+	Evidence []string
+}
+
+// Matcher collects hits of a catalog
+type Matcher struct {
+	catalog *Catalog
+	// issue ID : hit
+	hits map[string]*Hit
+}
+
+// Load returns the known issues catalog embedded in must-gather
+func Load() (*Catalog, error) {
+	catalog := &Catalog{}
+	err := yaml.UnmarshalStrict(embeddedKnownIssues, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse known issues: %w", err)
+	}
+	ids := map[string]bool{}
+	for index := range catalog.Issues {
+		issue := &catalog.Issues[index]
+		if ids[issue.ID] {
+			return nil, fmt.Errorf("known issue %s is duplicated", issue.ID)
+		}
+		ids[issue.ID] = true
+		if !issueLinkRegex.MatchString(issue.Link) {
+			return nil, fmt.Errorf("link of known issue %s is not a GitHub, Jira or Red Hat Knowledgebase issue: '%s'", issue.ID, issue.Link)
+		}
+		if len(issue.LogPattern) != 0 {
+			issue.logRegex, err = regexp.Compile(issue.LogPattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid logPattern of known issue %s: %w", issue.ID, err)
+			}
+		}
+		if len(issue.StatusPattern) != 0 {
+			issue.statusRegex, err = regexp.Compile(issue.StatusPattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid statusPattern of known issue %s: %w", issue.ID, err)
+			}
+		}
+	}
+	return catalog, nil
+}
+
+func NewMatcher(catalog *Catalog) *Matcher {
+	return &Matcher{catalog: catalog, hits: map[string]*Hit{}}
+}
+
+func (matcher *Matcher) addEvidence(issue *Issue, evidence string) {
+	hit, ok := matcher.hits[issue.ID]
+	if !ok {
+		hit = &Hit{Issue: issue}
+		matcher.hits[issue.ID] = hit
+	}
+	if len(hit.Evidence) < evidenceMax && !slices.Contains(hit.Evidence, evidence) {
+		hit.Evidence = append(hit.Evidence, evidence)
+	}
+}
+
+// MatchLogs matches each line of logs against issues log patterns, source identifies the logs
+func (matcher *Matcher) MatchLogs(source string, logs string) {
+	for index := range matcher.catalog.Issues {
+		issue := &matcher.catalog.Issues[index]
+		if issue.logRegex == nil {
+			continue
+		}
+		for _, line := range strings.Split(logs, "\n") {
+			if issue.logRegex.MatchString(line) {
+				matcher.addEvidence(issue, fmt.Sprintf("%s: %s", source, line))
+			}
+		}
+	}
+}
+
+// MatchStatus matches status of an object against issues status patterns of its kind
+func (matcher *Matcher) MatchStatus(kind string, namespace string, name string, status string) {
+	for index := range matcher.catalog.Issues {
+		issue := &matcher.catalog.Issues[index]
+		if issue.statusRegex == nil || !slices.Contains(issue.StatusKinds, kind) {
+			continue
+		}
+		if match := issue.statusRegex.FindString(status); len(match) != 0 {
+			matcher.addEvidence(issue, fmt.Sprintf("%s %s/%s status: %s", kind, namespace, name, status))
+		}
+	}
+}
+
+// Hits returns issues found, in catalog order
+func (matcher *Matcher) Hits() []*Hit {
+	var hits []*Hit
+	for _, issue := range matcher.catalog.Issues {
+		if hit, ok := matcher.hits[issue.ID]; ok {
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
@@ -0,0 +1,46 @@
+package knownissues
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	catalog, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Issues) == 0 {
+		t.Fatal("embedded catalog has no known issues")
+	}
+	for _, issue := range catalog.Issues {
+		if len(issue.Title) == 0 || len(issue.Remediation) == 0 {
+			t.Errorf("known issue %s must have title and remediation", issue.ID)
+		}
+		if issue.logRegex == nil && issue.statusRegex == nil {
+			t.Errorf("known issue %s must have logPattern or statusPattern", issue.ID)
+		}
+		if issue.statusRegex != nil && len(issue.StatusKinds) == 0 {
+			t.Errorf("known issue %s has statusPattern, but no statusKinds", issue.ID)
+		}
+	}
+}
+
+func TestIssueLinkRegex(t *testing.T) {
+	tests := []struct {
+		link  string
+		valid bool
+	}{
+		{link: "https://github.com/vmware-tanzu/velero/issues/5696", valid: true},
+		{link: "https://github.com/vmware-tanzu/velero/pull/8085", valid: true},
+		{link: "https://issues.redhat.com/browse/OADP-1", valid: true},
+		{link: "https://access.redhat.com/solutions/1", valid: true},
+		{link: "https://velero.io/docs/main/troubleshooting/", valid: false},
+		{link: "https://github.com/vmware-tanzu/velero", valid: false},
+		{link: "", valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			if valid := issueLinkRegex.MatchString(test.link); valid != test.valid {
+				t.Errorf("issueLinkRegex.MatchString(%q) = %v, want %v", test.link, valid, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package logs
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maximum affected resources kept per cluster
+	clusterResourcesMax = 10
+)
+
+// Entry is a line of Velero logrus text format, like
+// time="2025-01-30T12:00:00Z" level=error msg="Error backing up item" backup=openshift-adp/test error="..." logSource="pkg/backup/backup.go:123" name=pod-1 namespace=app resource=pods
+type Entry struct {
+	Time      time.Time
+	Level     string
+	Message   string
+	LogSource string
+	Backup    string
+	Restore   string
+	Resource  string
+	Namespace string
+	Name      string
+	Error     string
+	Raw       string
+}
+
+var signatureReplaces = []struct {
+	regex       *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`"[^"]*"`), `"<x>"`},
+	{regexp.MustCompile(`'[^']*'`), `'<x>'`},
+	{regexp.MustCompile(`\b[0-9a-f]{12,}\b`), "<hex>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+// ParseLine returns the Entry of a logrus text format line, false if line is not in that format
+func ParseLine(line string) (Entry, bool) {
+	fields := parseFields(line)
+	level, ok := fields["level"]
+	if !ok {
+		return Entry{}, false
+	}
+	entry := Entry{
+		Level:     level,
+		Message:   fields["msg"],
+		LogSource: fields["logSource"],
+		Backup:    fields["backup"],
+		Restore:   fields["restore"],
+		Resource:  fields["resource"],
+		Namespace: fields["namespace"],
+		Name:      fields["name"],
+		Error:     fields["error"],
+		Raw:       line,
+	}
+	if timestamp, err := time.Parse(time.RFC3339, fields["time"]); err == nil {
+		entry.Time = timestamp
+	}
+	return entry, true
+}
+
+// Parse returns entries of logrus text format lines, skipping other lines
+func Parse(text string) []Entry {
+	var entries []Entry
+	for _, line := range strings.Split(text, "\n") {
+		if entry, ok := ParseLine(line); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseFields returns key=value pairs of a line, values may be double quoted with escapes
+func parseFields(line string) map[string]string {
+	fields := map[string]string{}
+	for index := 0; index < len(line); {
+		for index < len(line) && line[index] == ' ' {
+			index++
+		}
+		equal := strings.IndexByte(line[index:], '=')
+		if equal <= 0 {
+			break
+		}
+		key := line[index : index+equal]
+		if strings.ContainsRune(key, ' ') {
+			break
+		}
+		index += equal + 1
+		if index < len(line) && line[index] == '"' {
+			end := index + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				end = len(line) - 1
+			}
+			value, err := strconv.Unquote(line[index : end+1])
+			if err != nil {
+				value = strings.Trim(line[index:end+1], `"`)
+			}
+			fields[key] = value
+			index = end + 1
+		} else {
+			end := strings.IndexByte(line[index:], ' ')
+			if end < 0 {
+				end = len(line) - index
+			}
+			fields[key] = line[index : index+end]
+			index += end
+		}
+	}
+	return fields
+}
+
+// Text returns message and error of an Entry
+func (entry Entry) Text() string {
+	if len(entry.Error) == 0 {
+		return entry.Message
+	}
+	return entry.Message + ": " + entry.Error
+}
+
+// AffectedResource returns the Kubernetes resource of an Entry, empty if none
+func (entry Entry) AffectedResource() string {
+	if len(entry.Resource) == 0 && len(entry.Name) == 0 {
+		return ""
+	}
+	name := entry.Name
+	if len(entry.Namespace) != 0 {
+		name = entry.Namespace + "/" + name
+	}
+	return strings.TrimSpace(entry.Resource + " " + name)
+}
+
+// Signature returns text of an Entry without variable parts, like names, numbers and timestamps
+func Signature(text string) string {
+	for _, replace := range signatureReplaces {
+		text = replace.regex.ReplaceAllString(text, replace.replacement)
+	}
+	return text
+}
+
+// Cluster groups entries with same level and signature
+type Cluster struct {
+	Signature string
+	Level     string
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Example   string
+	Resources []string
+	Sources   []string
+}
+
+// Clusters groups entries of multiple logs
+type Clusters struct {
+	clusters map[string]*Cluster
+}
+
+func NewClusters() *Clusters {
+	return &Clusters{clusters: map[string]*Cluster{}}
+}
+
+// Add groups entries of a log with level in levels, source identifies the log
+func (clusters *Clusters) Add(source string, entries []Entry, levels ...string) {
+	for _, entry := range entries {
+		if !slices.Contains(levels, entry.Level) {
+			continue
+		}
+		signature := Signature(entry.Text())
+		key := entry.Level + " " + signature
+		cluster, ok := clusters.clusters[key]
+		if !ok {
+			cluster = &Cluster{Signature: signature, Level: entry.Level, Example: entry.Text()}
+			clusters.clusters[key] = cluster
+		}
+		cluster.Count++
+		if !entry.Time.IsZero() {
+			if cluster.FirstSeen.IsZero() || entry.Time.Before(cluster.FirstSeen) {
+				cluster.FirstSeen = entry.Time
+			}
+			if entry.Time.After(cluster.LastSeen) {
+				cluster.LastSeen = entry.Time
+			}
+		}
+		if resource := entry.AffectedResource(); len(resource) != 0 && len(cluster.Resources) < clusterResourcesMax && !slices.Contains(cluster.Resources, resource) {
+			cluster.Resources = append(cluster.Resources, resource)
+		}
+		if !slices.Contains(cluster.Sources, source) {
+			cluster.Sources = append(cluster.Sources, source)
+		}
+	}
+}
+
+// Top returns the n clusters with most entries
+func (clusters *Clusters) Top(n int) []*Cluster {
+	var top []*Cluster
+	for _, cluster := range clusters.clusters {
+		top = append(top, cluster)
+	}
+	slices.SortFunc(top, func(a *Cluster, b *Cluster) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Signature, b.Signature)
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
@@ -0,0 +1,280 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		ok    bool
+		entry Entry
+	}{
+		{
+			name: "quoted and unquoted values",
+			line: `time="2025-01-30T12:00:00Z" level=error msg="Error backing up item" backup=openshift-adp/test logSource="pkg/backup/backup.go:123" name=pod-1 namespace=app resource=pods`,
+			ok:   true,
+			entry: Entry{
+				Time:      time.Date(2025, 1, 30, 12, 0, 0, 0, time.UTC),
+				Level:     "error",
+				Message:   "Error backing up item",
+				LogSource: "pkg/backup/backup.go:123",
+				Backup:    "openshift-adp/test",
+				Resource:  "pods",
+				Namespace: "app",
+				Name:      "pod-1",
+			},
+		},
+		{
+			name: "escaped quotes in value",
+			line: `level=error msg="error getting \"test\" volume" error="rpc error: code = Unknown desc = \"failed\""`,
+			ok:   true,
+			entry: Entry{
+				Level:   "error",
+				Message: `error getting "test" volume`,
+				Error:   `rpc error: code = Unknown desc = "failed"`,
+			},
+		},
+		{
+			name: "escaped backslash before closing quote",
+			line: `level=warning msg="path C:\\" restore=openshift-adp/test`,
+			ok:   true,
+			entry: Entry{
+				Level:   "warning",
+				Message: `path C:\`,
+				Restore: "openshift-adp/test",
+			},
+		},
+		{
+			name: "unterminated quoted value",
+			line: `level=info msg="never closed`,
+			ok:   true,
+			entry: Entry{
+				Level:   "info",
+				Message: "never closed",
+			},
+		},
+		{
+			name: "invalid time",
+			line: `time=yesterday level=info msg=started`,
+			ok:   true,
+			entry: Entry{
+				Level:   "info",
+				Message: "started",
+			},
+		},
+		{
+			name: "not logrus text format",
+			line: `I0130 12:00:00.000000       1 main.go:10] started`,
+			ok:   false,
+		},
+		{
+			name: "empty line",
+			line: "",
+			ok:   false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry, ok := ParseLine(test.line)
+			if ok != test.ok {
+				t.Fatalf("ParseLine() ok = %v, want %v", ok, test.ok)
+			}
+			if !ok {
+				return
+			}
+			test.entry.Raw = test.line
+			if entry != test.entry {
+				t.Errorf("ParseLine() = %#v, want %#v", entry, test.entry)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	text := "level=info msg=first\nnot a log line\n\nlevel=error msg=second\n"
+	entries := Parse(text)
+	if len(entries) != 2 {
+		t.Fatalf("Parse() returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Message != "first" || entries[1].Message != "second" {
+		t.Errorf("Parse() = %#v", entries)
+	}
+}
+
+func TestEntryText(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		text  string
+	}{
+		{
+			name:  "message only",
+			entry: Entry{Message: "Backup completed"},
+			text:  "Backup completed",
+		},
+		{
+			name:  "message and error",
+			entry: Entry{Message: "Error backing up item", Error: "timed out"},
+			text:  "Error backing up item: timed out",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if text := test.entry.Text(); text != test.text {
+				t.Errorf("Text() = %q, want %q", text, test.text)
+			}
+		})
+	}
+}
+
+func TestAffectedResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		resource string
+	}{
+		{
+			name:     "namespaced",
+			entry:    Entry{Resource: "pods", Namespace: "app", Name: "pod-1"},
+			resource: "pods app/pod-1",
+		},
+		{
+			name:     "cluster scoped",
+			entry:    Entry{Resource: "persistentvolumes", Name: "pv-1"},
+			resource: "persistentvolumes pv-1",
+		},
+		{
+			name:     "name only",
+			entry:    Entry{Name: "pod-1"},
+			resource: "pod-1",
+		},
+		{
+			name:     "none",
+			entry:    Entry{Namespace: "app"},
+			resource: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if resource := test.entry.AffectedResource(); resource != test.resource {
+				t.Errorf("AffectedResource() = %q, want %q", resource, test.resource)
+			}
+		})
+	}
+}
+
+func TestSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		signature string
+	}{
+		{
+			name:      "uuid",
+			text:      "pod 0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b not found",
+			signature: "pod <uuid> not found",
+		},
+		{
+			name:      "timestamp",
+			text:      "started at 2025-01-30T12:00:00.123Z",
+			signature: "started at <time>",
+		},
+		{
+			name:      "ip and port",
+			text:      "dial tcp 10.0.0.1:443: i/o timeout",
+			signature: "dial tcp <ip>: i/o timeout",
+		},
+		{
+			name:      "quoted names",
+			text:      `persistentvolumeclaims "data-1" already exists in 'app'`,
+			signature: `persistentvolumeclaims "<x>" already exists in '<x>'`,
+		},
+		{
+			name:      "hex and numbers",
+			text:      "snapshot 0123456789abcdef failed after 3 retries",
+			signature: "snapshot <hex> failed after <n> retries",
+		},
+		{
+			name:      "same signature for different values",
+			text:      `error restoring pods "app-2": timeout after 30s`,
+			signature: `error restoring pods "<x>": timeout after <n>s`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if signature := Signature(test.text); signature != test.signature {
+				t.Errorf("Signature() = %q, want %q", signature, test.signature)
+			}
+		})
+	}
+}
+
+func TestClustersCollapseIdenticalErrors(t *testing.T) {
+	lines := make([]string, 0, 10000)
+	for index := 0; index < 10000; index++ {
+		lines = append(lines, fmt.Sprintf(
+			`time="2025-01-30T12:%02d:%02dZ" level=error msg="Error backing up item" error="pods \"pod-%d\" not found" name=pod-%d namespace=app resource=pods`,
+			index/60%60, index%60, index, index,
+		))
+	}
+	clusters := NewClusters()
+	clusters.Add("Backup openshift-adp/test", Parse(strings.Join(lines, "\n")), "error")
+
+	top := clusters.Top(20)
+	if len(top) != 1 {
+		t.Fatalf("Top() returned %d clusters, want 1", len(top))
+	}
+	cluster := top[0]
+	if cluster.Count != 10000 {
+		t.Errorf("Count = %d, want 10000", cluster.Count)
+	}
+	if cluster.Signature != `Error backing up item: pods "<x>" not found` {
+		t.Errorf("Signature = %q", cluster.Signature)
+	}
+	if len(cluster.Resources) != clusterResourcesMax {
+		t.Errorf("kept %d resources, want %d", len(cluster.Resources), clusterResourcesMax)
+	}
+	if len(cluster.Sources) != 1 || cluster.Sources[0] != "Backup openshift-adp/test" {
+		t.Errorf("Sources = %v", cluster.Sources)
+	}
+	if !cluster.FirstSeen.Equal(time.Date(2025, 1, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("FirstSeen = %v", cluster.FirstSeen)
+	}
+	if !cluster.LastSeen.Equal(time.Date(2025, 1, 30, 12, 59, 59, 0, time.UTC)) {
+		t.Errorf("LastSeen = %v", cluster.LastSeen)
+	}
+}
+
+func TestClustersLevelsAndTop(t *testing.T) {
+	clusters := NewClusters()
+	clusters.Add("Backup a", Parse(strings.Join([]string{
+		`level=error msg="first error"`,
+		`level=error msg="second error"`,
+		`level=error msg="second error"`,
+		`level=warning msg="only warning"`,
+		`level=info msg="only info"`,
+	}, "\n")), "error")
+	clusters.Add("Restore b", Parse(`level=error msg="first error"`+"\n"+`level=error msg="first error"`), "error")
+
+	top := clusters.Top(20)
+	if len(top) != 2 {
+		t.Fatalf("Top() returned %d clusters, want 2", len(top))
+	}
+	if top[0].Signature != "first error" || top[0].Count != 3 {
+		t.Errorf("top[0] = %s %d, want first error 3", top[0].Signature, top[0].Count)
+	}
+	if len(top[0].Sources) != 2 {
+		t.Errorf("top[0].Sources = %v, want 2 sources", top[0].Sources)
+	}
+	if top[1].Signature != "second error" || top[1].Count != 2 {
+		t.Errorf("top[1] = %s %d, want second error 2", top[1].Signature, top[1].Count)
+	}
+	if top := clusters.Top(1); len(top) != 1 {
+		t.Errorf("Top(1) returned %d clusters, want 1", len(top))
+	}
+}
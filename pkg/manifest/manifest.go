@@ -0,0 +1,224 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const fileName = "manifest.json"
+
+// File is a file written by must-gather
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// API call or content that originated the file, like "list BackupList" or "logs"
+	Source string `json:"source"`
+	Step   string `json:"step,omitempty"`
+}
+
+// Failure is a file or API call that must-gather was unable to collect
+type Failure struct {
+	Step   string `json:"step,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Source string `json:"source,omitempty"`
+	Error  string `json:"error"`
+}
+
+// Step is a collection step of must-gather
+type Step struct {
+	Name string `json:"name"`
+	// output dir of cluster the step belongs to, empty for steps of all clusters
+	Scope    string    `json:"scope,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Duration string    `json:"duration"`
+}
+
+type Manifest struct {
+	Version  string            `json:"version"`
+	Flags    map[string]string `json:"flags"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Steps    []Step            `json:"steps"`
+	Files    []File            `json:"files"`
+	Failures []Failure         `json:"failures"`
+}
+
+var (
+	lock     sync.Mutex
+	manifest = &Manifest{Flags: map[string]string{}}
+	// file path : source of file
+	fileSources = map[string]string{}
+	// file path : step of file
+	fileSteps = map[string]string{}
+)
+
+// currentStep returns name of latest step of scope path is in, or latest step of all clusters
+func currentStep(path string) string {
+	path = filepath.Clean(path)
+	fallback := ""
+	for index := len(manifest.Steps) - 1; index >= 0; index-- {
+		step := manifest.Steps[index]
+		if len(step.Scope) == 0 {
+			if len(fallback) == 0 {
+				fallback = step.Name
+			}
+			continue
+		}
+		if strings.HasPrefix(path, filepath.Clean(step.Scope)) {
+			return step.Name
+		}
+	}
+	return fallback
+}
+
+// endCurrentStep ends open step of scope, or all open steps if scope is nil
+func endCurrentStep(scope *string, now time.Time) {
+	for index := range manifest.Steps {
+		step := &manifest.Steps[index]
+		if step.End.IsZero() && (scope == nil || step.Scope == *scope) {
+			step.End = now
+			step.Duration = step.End.Sub(step.Start).Round(time.Millisecond).String()
+		}
+	}
+}
+
+// Start records must-gather version, flags and collection start time
+func Start(version string, flags map[string]string) {
+	lock.Lock()
+	defer lock.Unlock()
+	manifest.Version = version
+	manifest.Flags = flags
+	manifest.Start = time.Now().UTC()
+}
+
+// StartStep ends the current collection step of scope and starts a new one. Scope is output dir of a cluster,
+// or empty for steps of all clusters
+func StartStep(scope string, name string) {
+	lock.Lock()
+	defer lock.Unlock()
+	now := time.Now().UTC()
+	endCurrentStep(&scope, now)
+	manifest.Steps = append(manifest.Steps, Step{Name: name, Scope: scope, Start: now})
+}
+
+// APICall returns API call that originated obj, like "list BackupList"
+func APICall(obj runtime.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if len(kind) == 0 {
+		kind = reflect.TypeOf(obj).Elem().Name()
+	}
+	if strings.HasSuffix(kind, "List") {
+		return "list " + kind
+	}
+	return "get " + kind
+}
+
+// RecordFile records source of a file written by must-gather
+func RecordFile(path string, source string) {
+	lock.Lock()
+	defer lock.Unlock()
+	path = filepath.Clean(path)
+	fileSources[path] = source
+	fileSteps[path] = currentStep(path)
+}
+
+// RecordFailure records something must-gather was unable to collect in current step of path
+func RecordFailure(path string, source string, err error) {
+	lock.Lock()
+	defer lock.Unlock()
+	manifest.Failures = append(manifest.Failures, Failure{
+		Step:   currentStep(path),
+		Path:   path,
+		Source: source,
+		Error:  err.Error(),
+	})
+}
+
+func checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Write ends collection and writes manifest.json in rootPath, listing every file under it.
+// Files not recorded by RecordFile are attributed to defaultSource (for example, oc adm inspect)
+func Write(rootPath string, defaultSource string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	manifest.End = time.Now().UTC()
+	endCurrentStep(nil, manifest.End)
+
+	// TODO permission
+	err := os.MkdirAll(rootPath, 0777)
+	if err != nil {
+		return err
+	}
+	manifest.Files = nil
+	err = filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return err
+		}
+		if relativePath == fileName {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		sum, err := checksum(path)
+		if err != nil {
+			return err
+		}
+		source, ok := fileSources[filepath.Clean(path)]
+		if !ok {
+			source = defaultSource
+		}
+		manifest.Files = append(manifest.Files, File{
+			Path:   filepath.ToSlash(relativePath),
+			Size:   info.Size(),
+			SHA256: sum,
+			Source: source,
+			Step:   fileSteps[filepath.Clean(path)],
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(manifest.Files, func(a File, b File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	// TODO permission
+	return os.WriteFile(filepath.Join(rootPath, fileName), content, 0644)
+}
@@ -0,0 +1,393 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/supportmatrix"
+)
+
+// ref https://velero.io/docs/main/restore-reference/#changing-pvpvc-storage-classes
+var changeStorageClassLabels = map[string]string{
+	"velero.io/plugin-config":        "",
+	"velero.io/change-storage-class": "RestoreItemAction",
+}
+
+// Cluster is a cluster of a kubeconfig context
+type Cluster struct {
+	Context   string
+	Client    client.Client
+	Clientset kubernetes.Interface
+	Artifacts *gather.Artifacts
+}
+
+// NewCluster returns clients of cluster, config is from gather.ClusterConfig
+func NewCluster(kubeContext string, kubeconfig string) (*Cluster, error) {
+	clusterConfig, err := gather.ClusterConfig(kubeconfig, kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme,
+		storagev1.AddToScheme,
+		operatorsv1alpha1.AddToScheme,
+		velerov1.AddToScheme,
+	} {
+		err = addToScheme(scheme)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clusterClient, err := client.New(clusterConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Cluster{Context: kubeContext, Client: clusterClient, Clientset: clientset, Artifacts: gather.NewArtifacts(clusterClient)}, nil
+}
+
+// report collects check results in markdown
+type report struct {
+	text  string
+	ready bool
+}
+
+func (r *report) section(title string) {
+	r.text += "\n## " + title + "\n\n"
+}
+
+func (r *report) ok(format string, args ...interface{}) {
+	r.text += "✅ " + fmt.Sprintf(format, args...) + "\n\n"
+}
+
+func (r *report) warn(format string, args ...interface{}) {
+	r.text += "⚠️ " + fmt.Sprintf(format, args...) + "\n\n"
+}
+
+func (r *report) fail(format string, args ...interface{}) {
+	r.ready = false
+	r.text += "❌ " + fmt.Sprintf(format, args...) + "\n\n"
+}
+
+func findBackup(cluster *Cluster, backupName string) (*velerov1.Backup, error) {
+	backupList := &velerov1.BackupList{}
+	err := gather.AllResources(cluster.Client, backupList)
+	if err != nil {
+		return nil, err
+	}
+	var found []velerov1.Backup
+	for _, backup := range backupList.Items {
+		if backup.Name == backupName {
+			found = append(found, backup)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no Backup named '%s' found in source cluster", backupName)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("more than one Backup named '%s' found in source cluster", backupName)
+	}
+}
+
+func oadpVersions(cluster *Cluster) ([]string, error) {
+	csvList := &operatorsv1alpha1.ClusterServiceVersionList{}
+	err := gather.AllResources(cluster.Client, csvList)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, csv := range csvList.Items {
+		if csv.Spec.DisplayName == "OADP Operator" && !slices.Contains(versions, csv.Spec.Version.String()) {
+			versions = append(versions, csv.Spec.Version.String())
+		}
+	}
+	return versions, nil
+}
+
+func checkStorageLocation(r *report, source *Cluster, target *Cluster, backup *velerov1.Backup) *velerov1.BackupStorageLocation {
+	sourceLocation := &velerov1.BackupStorageLocation{}
+	err := source.Client.Get(context.Background(), client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.StorageLocation}, sourceLocation)
+	if err != nil {
+		r.fail("Unable to get BackupStorageLocation **%s** of Backup in source cluster: %s", backup.Spec.StorageLocation, err)
+		return nil
+	}
+	sourceTarget := gather.StorageLocationTarget(sourceLocation)
+
+	targetLocationList := &velerov1.BackupStorageLocationList{}
+	err = gather.AllResources(target.Client, targetLocationList)
+	if err != nil {
+		r.fail("Unable to list BackupStorageLocations in target cluster: %s", err)
+		return nil
+	}
+	// a BackupStorageLocation that is Available is preferred over others pointing to same object storage
+	var targetLocation *velerov1.BackupStorageLocation
+	for index := range targetLocationList.Items {
+		location := &targetLocationList.Items[index]
+		if gather.StorageLocationTarget(location) != sourceTarget {
+			continue
+		}
+		if targetLocation == nil || (targetLocation.Status.Phase != velerov1.BackupStorageLocationPhaseAvailable &&
+			location.Status.Phase == velerov1.BackupStorageLocationPhaseAvailable) {
+			targetLocation = location
+		}
+	}
+	if targetLocation == nil {
+		r.fail("No BackupStorageLocation of target cluster points to `%s`, of source BackupStorageLocation **%s**", sourceTarget, sourceLocation.Name)
+		return nil
+	}
+	r.ok("BackupStorageLocation **%s/%s** of target cluster points to same `%s` as source", targetLocation.Namespace, targetLocation.Name, sourceTarget)
+	if targetLocation.Status.Phase != velerov1.BackupStorageLocationPhaseAvailable {
+		r.fail(
+			"BackupStorageLocation **%s/%s** of target cluster is not Available, its phase is **%s**",
+			targetLocation.Namespace, targetLocation.Name, targetLocation.Status.Phase,
+		)
+	}
+	if targetLocation.Spec.AccessMode != velerov1.BackupStorageLocationAccessModeReadOnly {
+		r.warn(
+			"BackupStorageLocation **%s/%s** of target cluster is not ReadOnly, setting `spec.accessMode: ReadOnly` avoids target cluster writing or deleting source Backups",
+			targetLocation.Namespace, targetLocation.Name,
+		)
+	}
+	return targetLocation
+}
+
+func checkSync(r *report, target *Cluster, backup *velerov1.Backup, targetLocation *velerov1.BackupStorageLocation) {
+	if targetLocation == nil {
+		r.fail("Backup can not be synced to target cluster, without an equivalent BackupStorageLocation")
+		return
+	}
+	syncedBackup := &velerov1.Backup{}
+	err := target.Client.Get(context.Background(), client.ObjectKey{Namespace: targetLocation.Namespace, Name: backup.Name}, syncedBackup)
+	if err != nil {
+		lastSynced := "never"
+		if targetLocation.Status.LastSyncedTime != nil {
+			lastSynced = targetLocation.Status.LastSyncedTime.String()
+		}
+		r.fail(
+			"Backup **%s** was not synced to target cluster namespace **%s** (last sync of BackupStorageLocation **%s**: %s). Check Backup sync period and velero logs of target cluster",
+			backup.Name, targetLocation.Namespace, targetLocation.Name, lastSynced,
+		)
+		return
+	}
+	r.ok("Backup **%s/%s** was synced to target cluster, its phase is **%s**", syncedBackup.Namespace, syncedBackup.Name, syncedBackup.Status.Phase)
+	if syncedBackup.Status.Phase != velerov1.BackupPhaseCompleted {
+		r.warn("Backup **%s** is not Completed, restore may be partial", backup.Name)
+	}
+}
+
+func checkStorageClasses(r *report, source *Cluster, target *Cluster, backup *velerov1.Backup, targetLocation *velerov1.BackupStorageLocation) {
+	resourceList, err := gather.BackupResourceList(source.Artifacts, backup)
+	if err != nil {
+		r.fail("Unable to get resource list of Backup from source cluster: %s", err)
+		return
+	}
+	// StorageClass : PVCs
+	sourceStorageClasses := map[string][]string{}
+	var notFound []string
+	for _, pvc := range resourceList["v1/PersistentVolumeClaim"] {
+		namespace, name, _ := strings.Cut(pvc, "/")
+		sourcePVC := &corev1.PersistentVolumeClaim{}
+		err = source.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, sourcePVC)
+		if err != nil {
+			notFound = append(notFound, pvc)
+			continue
+		}
+		if sourcePVC.Spec.StorageClassName == nil || len(*sourcePVC.Spec.StorageClassName) == 0 {
+			continue
+		}
+		sourceStorageClasses[*sourcePVC.Spec.StorageClassName] = append(sourceStorageClasses[*sourcePVC.Spec.StorageClassName], pvc)
+	}
+	if len(notFound) != 0 {
+		r.warn(
+			"StorageClass of PersistentVolumeClaims %s of Backup can not be checked, they were not found in source cluster",
+			strings.Join(notFound, ", "),
+		)
+	}
+	if len(sourceStorageClasses) == 0 {
+		r.ok("No PersistentVolumeClaim with StorageClass found in Backup")
+		return
+	}
+
+	storageClassList := &storagev1.StorageClassList{}
+	err = gather.AllResources(target.Client, storageClassList)
+	if err != nil {
+		r.fail("Unable to list StorageClasses in target cluster: %s", err)
+		return
+	}
+	targetStorageClasses := []string{}
+	for _, storageClass := range storageClassList.Items {
+		targetStorageClasses = append(targetStorageClasses, storageClass.Name)
+	}
+	// Velero only reads change-storage-class ConfigMaps of its namespace
+	veleroNamespace := backup.Namespace
+	if targetLocation != nil {
+		veleroNamespace = targetLocation.Namespace
+	}
+	configMapList := &corev1.ConfigMapList{}
+	err = target.Client.List(context.Background(), configMapList, client.InNamespace(veleroNamespace), client.MatchingLabels(changeStorageClassLabels))
+	if err != nil {
+		r.fail("Unable to list change-storage-class ConfigMaps in namespace **%s** of target cluster: %s", veleroNamespace, err)
+		return
+	}
+
+	for _, storageClass := range slices.Sorted(maps.Keys(sourceStorageClasses)) {
+		if slices.Contains(targetStorageClasses, storageClass) {
+			r.ok("StorageClass **%s** exists in target cluster", storageClass)
+			continue
+		}
+		mapped := false
+		for _, configMap := range configMapList.Items {
+			newStorageClass, ok := configMap.Data[storageClass]
+			if !ok {
+				continue
+			}
+			mapped = true
+			if slices.Contains(targetStorageClasses, newStorageClass) {
+				r.ok("StorageClass **%s** is changed to **%s** by ConfigMap **%s/%s** in target cluster", storageClass, newStorageClass, configMap.Namespace, configMap.Name)
+			} else {
+				r.fail(
+					"StorageClass **%s** is changed to **%s** by ConfigMap **%s/%s**, but **%s** does not exist in target cluster",
+					storageClass, newStorageClass, configMap.Namespace, configMap.Name, newStorageClass,
+				)
+			}
+		}
+		if !mapped {
+			r.fail(
+				"StorageClass **%s** (used by %s) does not exist in target cluster, and no change-storage-class ConfigMap in namespace **%s** maps it",
+				storageClass, strings.Join(sourceStorageClasses[storageClass], ", "), veleroNamespace,
+			)
+		}
+	}
+}
+
+func checkAPIs(r *report, source *Cluster, target *Cluster, backup *velerov1.Backup) {
+	resourceList, err := gather.BackupResourceList(source.Artifacts, backup)
+	if err != nil {
+		r.fail("Unable to get resource list of Backup from source cluster: %s", err)
+		return
+	}
+	served, err := gather.ServedKinds(target.Clientset.Discovery())
+	if err != nil {
+		r.warn("Some API groups of target cluster could not be discovered: %s", err)
+	}
+	missing := 0
+	for _, key := range slices.Sorted(maps.Keys(resourceList)) {
+		gvk, err := gather.ResourceListGroupVersionKind(key)
+		if err != nil {
+			r.warn("%s", err)
+			continue
+		}
+		if slices.Contains(served[gvk.GroupVersion()], gvk.Kind) {
+			continue
+		}
+		missing++
+		group := gvk.Group
+		if len(group) == 0 {
+			group = "core"
+		}
+		if versions := gather.ServedVersions(served, gvk.Group); len(versions) != 0 {
+			slices.Sort(versions)
+			r.fail(
+				"%s (%d backed up) is not served by target cluster, API group **%s** is served with versions %s",
+				gvkText(gvk), len(resourceList[key]), group, strings.Join(versions, ", "),
+			)
+		} else {
+			r.fail(
+				"%s (%d backed up) is not served by target cluster, API group **%s** does not exist, check if its CustomResourceDefinition or operator is installed",
+				gvkText(gvk), len(resourceList[key]), group,
+			)
+		}
+	}
+	if missing == 0 {
+		r.ok("All %d kinds of Backup are served by target cluster", len(resourceList))
+	}
+}
+
+func gvkText(gvk schema.GroupVersionKind) string {
+	return fmt.Sprintf("**%s** `%s`", gvk.Kind, gvk.GroupVersion().String())
+}
+
+func checkOADPVersions(r *report, source *Cluster, target *Cluster) {
+	sourceVersions, err := oadpVersions(source)
+	if err != nil {
+		r.fail("Unable to list ClusterServiceVersions in source cluster: %s", err)
+		return
+	}
+	targetVersions, err := oadpVersions(target)
+	if err != nil {
+		r.fail("Unable to list ClusterServiceVersions in target cluster: %s", err)
+		return
+	}
+	if len(targetVersions) == 0 {
+		r.fail("OADP operator is not installed in target cluster")
+		return
+	}
+	if len(sourceVersions) == 0 {
+		r.warn("OADP operator was not found in source cluster, Backup may be from upstream Velero")
+		return
+	}
+	for _, targetVersion := range targetVersions {
+		for _, sourceVersion := range sourceVersions {
+			if supportmatrix.MinorVersion(targetVersion) == supportmatrix.MinorVersion(sourceVersion) {
+				r.ok("OADP version **%s** of target cluster is compatible with version **%s** of source cluster", targetVersion, sourceVersion)
+				return
+			}
+		}
+	}
+	r.fail(
+		"OADP versions of target cluster (%s) do not match minor version of source cluster (%s)",
+		strings.Join(targetVersions, ", "), strings.Join(sourceVersions, ", "),
+	)
+}
+
+// Check returns markdown report of whether target cluster is ready to restore Backup of source cluster
+func Check(source *Cluster, target *Cluster, backupName string) (string, bool, error) {
+	backup, err := findBackup(source, backupName)
+	if err != nil {
+		return "", false, err
+	}
+	r := &report{
+		text: fmt.Sprintf(
+			"# OADP migration readiness check\n\nBackup **%s/%s** (phase **%s**) from context **%s** to context **%s**\n",
+			backup.Namespace, backup.Name, backup.Status.Phase, source.Context, target.Context,
+		),
+		ready: true,
+	}
+	r.section("BackupStorageLocation")
+	targetLocation := checkStorageLocation(r, source, target, backup)
+	r.section("Backup sync")
+	checkSync(r, target, backup, targetLocation)
+	r.section("StorageClasses")
+	checkStorageClasses(r, source, target, backup, targetLocation)
+	r.section("APIs of backed up resources")
+	checkAPIs(r, source, target, backup)
+	r.section("OADP version")
+	checkOADPVersions(r, source, target)
+
+	r.section("Result")
+	if r.ready {
+		r.text += "✅ Target cluster is ready to restore Backup\n"
+	} else {
+		r.text += "❌ Target cluster is not ready to restore Backup, fix errors above\n"
+	}
+	return r.text, r.ready, nil
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/migrate"
+)
+
+var (
+	SourceContext string
+	TargetContext string
+	BackupName    string
+
+	MigrateCheckCLI = &cobra.Command{
+		Use:   "migrate-check --source <context> --target <context> --backup <name>",
+		Short: "Check if target cluster is ready to restore a Backup of source cluster",
+		Long: `Check if target cluster is ready to restore a Backup of source cluster
+
+Checks if target cluster has a BackupStorageLocation pointing to same bucket and prefix, Backup synced,
+StorageClasses (or change-storage-class ConfigMaps), APIs of backed up resources and a compatible OADP version.`,
+		Args: cobra.NoArgs,
+		Example: `  # check before migrating namespaces of Backup my-backup
+  /usr/bin/gather migrate-check --source source-cluster --target target-cluster --backup my-backup`,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(SourceContext) == 0 || len(TargetContext) == 0 || len(BackupName) == 0 {
+				err := fmt.Errorf("--source, --target and --backup flags are required")
+				fmt.Println(err)
+				return err
+			}
+			source, err := migrate.NewCluster(SourceContext, Kubeconfig)
+			if err != nil {
+				fmt.Printf("Unable to connect to source cluster of context %s: %v\n", SourceContext, err)
+				return err
+			}
+			target, err := migrate.NewCluster(TargetContext, Kubeconfig)
+			if err != nil {
+				fmt.Printf("Unable to connect to target cluster of context %s: %v\n", TargetContext, err)
+				return err
+			}
+			report, ready, err := migrate.Check(source, target, BackupName)
+			if err != nil {
+				fmt.Printf("Unable to check migration readiness: %v\n", err)
+				return err
+			}
+			fmt.Print(report)
+			if !ready {
+				return fmt.Errorf("target cluster is not ready to restore Backup %s", BackupName)
+			}
+			return nil
+		},
+	}
+)
@@ -0,0 +1,104 @@
+package supportmatrix
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed support-matrix.yaml
+var embeddedSupportMatrix []byte
+
+type OADPRelease struct {
+	Version   string            `json:"version"`
+	Velero    string            `json:"velero"`
+	OpenShift []string          `json:"openshift"`
+	Plugins   map[string]string `json:"plugins"`
+}
+
+type OpenShiftRelease struct {
+	Version    string   `json:"version"`
+	CSIDrivers []string `json:"csiDrivers"`
+}
+
+type SupportMatrix struct {
+	Version   string             `json:"version"`
+	OADP      []OADPRelease      `json:"oadp"`
+	OpenShift []OpenShiftRelease `json:"openshift"`
+	// file the support matrix was loaded from, empty if embedded one
+	Source string `json:"-"`
+}
+
+// Load returns support matrix from file, or the one embedded in must-gather if file is empty
+func Load(file string) (*SupportMatrix, error) {
+	data := embeddedSupportMatrix
+	if len(file) != 0 {
+		var err error
+		data, err = os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+	supportMatrix := &SupportMatrix{Source: file}
+	err := yaml.Unmarshal(data, supportMatrix)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse support matrix: %w", err)
+	}
+	return supportMatrix, nil
+}
+
+// MinorVersion returns <major>.<minor> of a version, like 1.4 for 1.4.2 or v1.4.2-rc1
+func MinorVersion(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func (supportMatrix *SupportMatrix) OADPRelease(oadpVersion string) (OADPRelease, bool) {
+	for _, release := range supportMatrix.OADP {
+		if release.Version == MinorVersion(oadpVersion) {
+			return release, true
+		}
+	}
+	return OADPRelease{}, false
+}
+
+func (supportMatrix *SupportMatrix) OpenShiftRelease(openShiftVersion string) (OpenShiftRelease, bool) {
+	for _, release := range supportMatrix.OpenShift {
+		if release.Version == MinorVersion(openShiftVersion) {
+			return release, true
+		}
+	}
+	return OpenShiftRelease{}, false
+}
+
+// LatestOpenShiftVersion returns the most recent OpenShift version of the support matrix
+func (supportMatrix *SupportMatrix) LatestOpenShiftVersion() string {
+	latest := ""
+	for _, release := range supportMatrix.OpenShift {
+		if len(latest) == 0 || compareMinorVersions(release.Version, latest) > 0 {
+			latest = release.Version
+		}
+	}
+	return latest
+}
+
+func (release OADPRelease) SupportsOpenShift(openShiftVersion string) bool {
+	return slices.Contains(release.OpenShift, MinorVersion(openShiftVersion))
+}
+
+func compareMinorVersions(a string, b string) int {
+	var aMajor, aMinor, bMajor, bMinor int
+	_, _ = fmt.Sscanf(a, "%d.%d", &aMajor, &aMinor)
+	_, _ = fmt.Sscanf(b, "%d.%d", &bMajor, &bMinor)
+	if aMajor != bMajor {
+		return aMajor - bMajor
+	}
+	return aMinor - bMinor
+}
@@ -0,0 +1,191 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ACM cluster-backup-operator Velero Schedule names : hub backup type
+var acmScheduleTypes = map[string]string{
+	"acm-credentials-schedule":       "credentials",
+	"acm-managed-clusters-schedule":  "managed clusters",
+	"acm-resources-schedule":         "resources",
+	"acm-resources-generic-schedule": "generic resources",
+	"acm-validation-policy-schedule": "validation",
+}
+
+const acmGroup = "cluster.open-cluster-management.io"
+
+func (summary *Summary) ReplaceACMSection(
+	outputPath string,
+	foundACM bool,
+	backupScheduleList *unstructured.UnstructuredList,
+	acmRestoreList *unstructured.UnstructuredList,
+	scheduleList *velerov1.ScheduleList,
+	backupList *velerov1.BackupList,
+) {
+	if !foundACM {
+		summary.replaces["ACM"] = "Advanced Cluster Management for Kubernetes was not found installed in the cluster"
+		return
+	}
+
+	// namespace : objects
+	backupSchedulesByNamespace := map[string][]unstructured.Unstructured{}
+	acmRestoresByNamespace := map[string][]unstructured.Unstructured{}
+
+	summary.replaces["ACM"] += "#### BackupSchedules\n\n"
+	if backupScheduleList == nil || len(backupScheduleList.Items) == 0 {
+		summary.replaces["ACM"] += "No BackupSchedule was found in the cluster, hub is not backed up\n\n"
+	} else {
+		summary.replaces["ACM"] += "| Namespace | Name | spec.veleroSchedule | spec.veleroTtl | status.phase | status.lastMessage |\n| --- | --- | --- | --- | --- | --- |\n"
+		for _, backupSchedule := range backupScheduleList.Items {
+			backupSchedulesByNamespace[backupSchedule.GetNamespace()] = append(backupSchedulesByNamespace[backupSchedule.GetNamespace()], backupSchedule)
+			cron, _, _ := unstructured.NestedString(backupSchedule.Object, "spec", "veleroSchedule")
+			ttl, _, _ := unstructured.NestedString(backupSchedule.Object, "spec", "veleroTtl")
+			phase, _, _ := unstructured.NestedString(backupSchedule.Object, "status", "phase")
+			message, _, _ := unstructured.NestedString(backupSchedule.Object, "status", "lastMessage")
+
+			phaseText := "✅ " + phase
+			if phase != "Enabled" {
+				phaseText = "❌ " + phase
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ ACM BackupSchedule **%v** in **%v** namespace is in **%v** phase: %s\n\n",
+					backupSchedule.GetName(), backupSchedule.GetNamespace(), phase, message,
+				)
+			}
+			summary.replaces["ACM"] += fmt.Sprintf(
+				"| %v | %v | %v | %v | %s | %v |\n",
+				backupSchedule.GetNamespace(), backupSchedule.GetName(), cron, ttl, phaseText, message,
+			)
+		}
+		summary.replaces["ACM"] += "\n"
+	}
+
+	summary.replaces["ACM"] += "#### Hub backup chain\n\n"
+	// ACM Velero Schedule name : latest Backup created by it
+	latestBackups := map[string]*velerov1.Backup{}
+	if backupList != nil {
+		for _, backup := range backupList.Items {
+			scheduleName := backup.Labels[velerov1.ScheduleNameLabel]
+			if _, ok := acmScheduleTypes[scheduleName]; !ok {
+				continue
+			}
+			latest := latestBackups[scheduleName]
+			if latest == nil || backup.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+				latestBackups[scheduleName] = &backup
+			}
+		}
+	}
+	// ACM Velero Schedule name : Schedule
+	acmSchedules := map[string]velerov1.Schedule{}
+	if scheduleList != nil {
+		for _, schedule := range scheduleList.Items {
+			if _, ok := acmScheduleTypes[schedule.Name]; ok {
+				acmSchedules[schedule.Name] = schedule
+			}
+		}
+	}
+	summary.replaces["ACM"] += "| type | Schedule | Schedule status.phase | last Backup | last Backup status.phase | last Backup completion |\n| --- | --- | --- | --- | --- | --- |\n"
+	for _, scheduleName := range slices.Sorted(maps.Keys(acmScheduleTypes)) {
+		scheduleType := acmScheduleTypes[scheduleName]
+		schedule, ok := acmSchedules[scheduleName]
+		if !ok {
+			// validation Schedule is only created when BackupSchedule uses a policy
+			if scheduleName == "acm-validation-policy-schedule" {
+				summary.replaces["ACM"] += fmt.Sprintf("| %s | - not created (BackupSchedule uses no policy) | - | - | - | - |\n", scheduleType)
+				continue
+			}
+			if len(backupSchedulesByNamespace) != 0 {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ ACM BackupSchedule exists, but Velero Schedule **%v** for **%v** hub backups was not found\n\n",
+					scheduleName, scheduleType,
+				)
+			}
+			summary.replaces["ACM"] += fmt.Sprintf("| %s | ❌ %s not found | - | - | - | - |\n", scheduleType, scheduleName)
+			continue
+		}
+		schedulePhase := string(schedule.Status.Phase)
+		backupText, backupPhaseText, completionText := "❌ none", "-", "-"
+		if backup := latestBackups[scheduleName]; backup != nil {
+			backupText = fmt.Sprintf("%s/%s", backup.Namespace, backup.Name)
+			switch backup.Status.Phase {
+			case velerov1.BackupPhaseCompleted:
+				backupPhaseText = "✅ " + string(backup.Status.Phase)
+			case velerov1.BackupPhaseFailed, velerov1.BackupPhasePartiallyFailed, velerov1.BackupPhaseFailedValidation:
+				backupPhaseText = "❌ " + string(backup.Status.Phase)
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ Latest ACM **%v** hub Backup **%v** in **%v** namespace is in **%v** phase\n\n",
+					scheduleType, backup.Name, backup.Namespace, backup.Status.Phase,
+				)
+			default:
+				// New, InProgress and other phases of a running Backup
+				backupPhaseText = string(backup.Status.Phase)
+			}
+			if backup.Status.CompletionTimestamp != nil {
+				completionText = backup.Status.CompletionTimestamp.String()
+			}
+		} else {
+			summary.replaces["ERRORS"] += fmt.Sprintf(
+				"⚠️ Velero Schedule **%v** for ACM **%v** hub backups has not created any Backup\n\n",
+				scheduleName, scheduleType,
+			)
+		}
+		summary.replaces["ACM"] += fmt.Sprintf(
+			"| %s | %s/%s | %s | %s | %s | %s |\n",
+			scheduleType, schedule.Namespace, schedule.Name, schedulePhase, backupText, backupPhaseText, completionText,
+		)
+	}
+	summary.replaces["ACM"] += "\n"
+
+	summary.replaces["ACM"] += "#### Restores\n\n"
+	if acmRestoreList == nil || len(acmRestoreList.Items) == 0 {
+		summary.replaces["ACM"] += "No ACM Restore was found in the cluster\n\n"
+	} else {
+		summary.replaces["ACM"] += "| Namespace | Name | spec.veleroManagedClustersBackupName | spec.veleroCredentialsBackupName | spec.veleroResourcesBackupName | status.phase | status.lastMessage |\n| --- | --- | --- | --- | --- | --- | --- |\n"
+		for _, acmRestore := range acmRestoreList.Items {
+			acmRestoresByNamespace[acmRestore.GetNamespace()] = append(acmRestoresByNamespace[acmRestore.GetNamespace()], acmRestore)
+			managedClusters, _, _ := unstructured.NestedString(acmRestore.Object, "spec", "veleroManagedClustersBackupName")
+			credentials, _, _ := unstructured.NestedString(acmRestore.Object, "spec", "veleroCredentialsBackupName")
+			resources, _, _ := unstructured.NestedString(acmRestore.Object, "spec", "veleroResourcesBackupName")
+			phase, _, _ := unstructured.NestedString(acmRestore.Object, "status", "phase")
+			message, _, _ := unstructured.NestedString(acmRestore.Object, "status", "lastMessage")
+
+			var phaseText string
+			switch phase {
+			case "Finished", "Enabled":
+				phaseText = "✅ " + phase
+			case "Error", "FinishedWithErrors":
+				phaseText = "❌ " + phase
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ ACM Restore **%v** in **%v** namespace is in **%v** phase: %s\n\n",
+					acmRestore.GetName(), acmRestore.GetNamespace(), phase, message,
+				)
+			default:
+				phaseText = "⚠️ " + phase
+			}
+			summary.replaces["ACM"] += fmt.Sprintf(
+				"| %v | %v | %v | %v | %v | %s | %v |\n",
+				acmRestore.GetNamespace(), acmRestore.GetName(), managedClusters, credentials, resources, phaseText, message,
+			)
+		}
+		summary.replaces["ACM"] += "\n"
+	}
+
+	namespaces := slices.Concat(slices.Collect(maps.Keys(backupSchedulesByNamespace)), slices.Collect(maps.Keys(acmRestoresByNamespace)))
+	slices.Sort(namespaces)
+	for _, namespace := range slices.Compact(namespaces) {
+		var links []string
+		if len(backupSchedulesByNamespace[namespace]) != 0 {
+			links = append(links, summary.unstructuredYAML(outputPath, namespace, acmGroup, "backupschedules", backupSchedulesByNamespace[namespace]))
+		}
+		if len(acmRestoresByNamespace[namespace]) != 0 {
+			links = append(links, summary.unstructuredYAML(outputPath, namespace, acmGroup, "restores", acmRestoresByNamespace[namespace]))
+		}
+		summary.replaces["ACM"] += fmt.Sprintf("For more information about **%v** namespace, check %s\n\n", namespace, strings.Join(links, ", "))
+	}
+}
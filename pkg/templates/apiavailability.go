@@ -0,0 +1,213 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	oadpv1alpha1 "github.com/openshift/oadp-operator/api/v1alpha1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"github.com/vmware-tanzu/velero/pkg/util/collections"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+)
+
+// enableAPIGroupVersions returns if any DataProtectionApplication enables EnableAPIGroupVersions feature flag
+func enableAPIGroupVersions(dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList) bool {
+	if dataProtectionApplicationList == nil {
+		return false
+	}
+	for _, dpa := range dataProtectionApplicationList.Items {
+		if dpa.Spec.Configuration == nil || dpa.Spec.Configuration.Velero == nil {
+			continue
+		}
+		if dpa.Spec.Configuration.Velero.HasFeatureFlag(velerov1.APIGroupVersionsFeatureFlag) ||
+			len(dpa.Spec.Configuration.Velero.RestoreResourcesVersionPriority) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// crdVersionText returns why a CustomResourceDefinition does not serve a group version kind, empty if no CRD defines it
+func crdVersionText(crdList *apiextensionsv1.CustomResourceDefinitionList, gvk schema.GroupVersionKind) string {
+	if crdList == nil {
+		return ""
+	}
+	for _, crd := range crdList.Items {
+		if crd.Spec.Group != gvk.Group || crd.Spec.Names.Kind != gvk.Kind {
+			continue
+		}
+		var served []string
+		for _, version := range crd.Spec.Versions {
+			if version.Name == gvk.Version && !version.Served {
+				return fmt.Sprintf("CustomResourceDefinition **%s** has version %s, but it is not served", crd.Name, gvk.Version)
+			}
+			if version.Served {
+				served = append(served, version.Name)
+			}
+		}
+		return fmt.Sprintf("CustomResourceDefinition **%s** does not have version %s, it serves %s", crd.Name, gvk.Version, strings.Join(served, ", "))
+	}
+	return ""
+}
+
+// restoreIncludesKind returns if Restore resource filters include kind, Velero matches them against <resource>.<group>.
+// Filters are not resolved by discovery, like Velero does, because kinds not served by cluster have no discovery information
+func restoreIncludesKind(restore *velerov1.Restore, gvk schema.GroupVersionKind) bool {
+	resources := collections.NewIncludesExcludes().Includes(restore.Spec.IncludedResources...).Excludes(restore.Spec.ExcludedResources...)
+	excludedResources := collections.NewIncludesExcludes().Excludes(restore.Spec.ExcludedResources...)
+	plural, singular := apimeta.UnsafeGuessKindToResource(gvk)
+	names := []string{plural.GroupResource().String(), singular.GroupResource().String()}
+	return slices.ContainsFunc(names, resources.ShouldInclude) && !slices.ContainsFunc(names, func(name string) bool {
+		return !excludedResources.ShouldInclude(name)
+	})
+}
+
+// ReplaceAPIAvailabilitySection checks if kinds of Backups used by Restores are served by cluster API
+func (summary *Summary) ReplaceAPIAvailabilitySection(
+	artifacts *gather.Artifacts,
+	clusterConfig *rest.Config,
+	restoreList *velerov1.RestoreList,
+	backupList *velerov1.BackupList,
+	dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList,
+) {
+	if restoreList == nil || len(restoreList.Items) == 0 {
+		summary.replaces["API_AVAILABILITY"] = "❌ No Restore was found in the cluster"
+		return
+	}
+
+	apiextensionsClient, err := apiextensionsclientset.NewForConfig(clusterConfig)
+	if err != nil {
+		fmt.Println(err)
+		summary.replaces["API_AVAILABILITY"] = fmt.Sprintf("❌ Unable to create apiextensions client: %s", err)
+		return
+	}
+	served, err := gather.ServedKinds(apiextensionsClient.Discovery())
+	if err != nil {
+		fmt.Println(err)
+		if len(served) == 0 {
+			summary.replaces["API_AVAILABILITY"] = fmt.Sprintf("❌ Unable to discover cluster API: %s", err)
+			return
+		}
+		summary.replaces["API_AVAILABILITY"] += fmt.Sprintf("⚠️ Some API groups could not be discovered: %s\n\n", err)
+	}
+	crdList, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().List(context.Background(), v1.ListOptions{})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	groupVersionsEnabled := enableAPIGroupVersions(dataProtectionApplicationList)
+	if groupVersionsEnabled {
+		summary.replaces["API_AVAILABILITY"] += fmt.Sprintf(
+			"`%s` feature flag is enabled: Backups taken with it include all served versions of each API group, "+
+				"and Restores use the first version served by this cluster (by `restoreResourcesVersionPriority`, this cluster preferred version, "+
+				"Backup cluster preferred version, then other common versions). The flag must be enabled in both Backup and Restore clusters\n\n",
+			velerov1.APIGroupVersionsFeatureFlag,
+		)
+	} else {
+		summary.replaces["API_AVAILABILITY"] += fmt.Sprintf(
+			"`%s` feature flag is not enabled: Backups include only preferred version of each API group, "+
+				"that must be served by this cluster to be restored\n\n",
+			velerov1.APIGroupVersionsFeatureFlag,
+		)
+	}
+
+	for _, restore := range restoreList.Items {
+		if len(restore.Spec.BackupName) == 0 {
+			continue
+		}
+		var backup *velerov1.Backup
+		if backupList != nil {
+			for index := range backupList.Items {
+				if backupList.Items[index].Namespace == restore.Namespace && backupList.Items[index].Name == restore.Spec.BackupName {
+					backup = &backupList.Items[index]
+				}
+			}
+		}
+		if backup == nil {
+			summary.replaces["API_AVAILABILITY"] += fmt.Sprintf(
+				"⚠️ Backup **%s** of Restore **%s** in **%s** namespace was not found in the cluster\n\n",
+				restore.Spec.BackupName, restore.Name, restore.Namespace,
+			)
+			continue
+		}
+		resourceList, err := gather.BackupResourceList(artifacts, backup)
+		if err != nil {
+			fmt.Println(err)
+			summary.replaces["API_AVAILABILITY"] += fmt.Sprintf(
+				"⚠️ Unable to get resource list of Backup **%s** in **%s** namespace: %s\n\n", backup.Name, backup.Namespace, err,
+			)
+			continue
+		}
+
+		restoredKinds := 0
+		unavailableText := ""
+		result := "⚠️"
+		for _, key := range slices.Sorted(maps.Keys(resourceList)) {
+			gvk, err := gather.ResourceListGroupVersionKind(key)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			if !restoreIncludesKind(&restore, gvk) {
+				continue
+			}
+			restoredKinds++
+			if slices.Contains(served[gvk.GroupVersion()], gvk.Kind) {
+				continue
+			}
+			group := gvk.Group
+			if len(group) == 0 {
+				group = "core"
+			}
+			reason := fmt.Sprintf("API group **%s** is not served", group)
+			kindResult := "❌"
+			if versions := gather.ServedVersions(served, gvk.Group); len(versions) != 0 {
+				slices.Sort(versions)
+				reason = fmt.Sprintf("API group **%s** is served with versions %s", group, strings.Join(versions, ", "))
+				if groupVersionsEnabled {
+					// Backup may include other versions of the group
+					kindResult = "⚠️"
+					reason += ", it can only be restored if Backup was taken with `" + velerov1.APIGroupVersionsFeatureFlag + "` enabled"
+				} else {
+					reason += ", enable `" + velerov1.APIGroupVersionsFeatureFlag + "` feature flag in both clusters before taking Backups"
+				}
+			}
+			if kindResult == "❌" {
+				result = "❌"
+			}
+			if crdText := crdVersionText(crdList, gvk); len(crdText) != 0 {
+				reason += "; " + crdText
+			} else if len(gather.ServedVersions(served, gvk.Group)) == 0 && len(gvk.Group) != 0 {
+				reason += "; install its CustomResourceDefinition or operator before restoring"
+			}
+			unavailableText += fmt.Sprintf("| %s | `%s` | %d | %s %s |\n", gvk.Kind, gvk.GroupVersion().String(), len(resourceList[key]), kindResult, reason)
+		}
+
+		if len(unavailableText) == 0 {
+			summary.replaces["API_AVAILABILITY"] += fmt.Sprintf(
+				"✅ All %d kinds of Backup **%s** restored by Restore **%s** in **%s** namespace are served by the cluster\n\n",
+				restoredKinds, backup.Name, restore.Name, restore.Namespace,
+			)
+			continue
+		}
+		summary.replaces["ERRORS"] += fmt.Sprintf(
+			"%s Restore **%s** in **%s** namespace restores kinds of Backup **%s** not served by the cluster, check restore API availability section\n\n",
+			result, restore.Name, restore.Namespace, backup.Name,
+		)
+		summary.replaces["API_AVAILABILITY"] += fmt.Sprintf(
+			"Kinds of Backup **%s** restored by Restore **%s** in **%s** namespace that can not be restored in the cluster\n\n"+
+				"| kind | version | backed up | reason |\n| --- | --- | --- | --- |\n%s\n",
+			backup.Name, restore.Name, restore.Namespace, unavailableText,
+		)
+	}
+}
@@ -0,0 +1,196 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// share of size budget reserved to summary file
+	summaryBudgetShare = 10
+	// maximum share of size budget of a single logs file
+	logsBudgetShare = 20
+	// logs files are skipped if less than this bytes of size budget is left
+	logsMinimumSize = 4 * 1024
+	// share of size budget reserved to files outside clusters directories, like clusters summary and manifest
+	sharedBudgetShare = 20
+)
+
+var (
+	// maximum size of must-gather output in bytes, zero means no limit
+	totalSizeBudget int64
+	// maximum size of must-gather output of each cluster in bytes, zero means no limit
+	sizeBudget int64
+)
+
+// SetSizeBudget sets maximum size of must-gather output in bytes, zero means no limit.
+// Size budget is split evenly between gathered clusters, after reserving a share to files outside clusters directories
+func SetSizeBudget(maxSize int64, clusters int) {
+	totalSizeBudget = maxSize
+	sizeBudget = (maxSize - maxSize/sharedBudgetShare) / int64(max(clusters, 1))
+}
+
+// remainingSize returns bytes left in size budget, after summary reserve
+func (summary *Summary) remainingSize() int64 {
+	return sizeBudget - sizeBudget/summaryBudgetShare - summary.writtenSize
+}
+
+func (summary *Summary) addWrittenSize(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	summary.writtenSize += info.Size()
+}
+
+// truncateLogs keeps head and tail lines of logs that fit in limit bytes, with a marker in between
+func truncateLogs(logs string, limit int64) string {
+	marker := "\n[... OADP must-gather truncated %d bytes of logs to stay within --max-size ...]\n\n"
+	half := int(limit-int64(len(marker))-20) / 2
+	if half <= 0 {
+		return ""
+	}
+	head := logs[:half]
+	if index := strings.LastIndex(head, "\n"); index != -1 {
+		head = head[:index+1]
+	} else {
+		// do not split multi-byte characters
+		for len(head) > 0 && !utf8.RuneStart(logs[len(head)]) {
+			head = head[:len(head)-1]
+		}
+	}
+	tail := logs[len(logs)-half:]
+	if index := strings.Index(tail, "\n"); index != -1 {
+		tail = tail[index+1:]
+	} else {
+		for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+			tail = tail[1:]
+		}
+	}
+	return head + fmt.Sprintf(marker, len(logs)-len(head)-len(tail)) + tail
+}
+
+// budgetedLogs returns logs that fit in size budget and if they were skipped
+func (summary *Summary) budgetedLogs(path string, logs string) (string, bool) {
+	if sizeBudget == 0 {
+		return logs, false
+	}
+	limit := min(summary.remainingSize(), sizeBudget/logsBudgetShare)
+	if limit < logsMinimumSize {
+		summary.budgetNotes = append(summary.budgetNotes, fmt.Sprintf("Skipped `%s` (%s)", path, formatBytes(int64(len(logs)))))
+		return "", true
+	}
+	if int64(len(logs)) <= limit {
+		return logs, false
+	}
+	truncated := truncateLogs(logs, limit)
+	summary.budgetNotes = append(summary.budgetNotes, fmt.Sprintf(
+		"Truncated `%s` from %s to %s, keeping its first and last lines",
+		path, formatBytes(int64(len(logs))), formatBytes(int64(len(truncated))),
+	))
+	return truncated, false
+}
+
+// BudgetLogs truncates or skips logs files written by summary sections to fit in what is left of size budget.
+// Called after all other files are written, so summary and CustomResources have priority over logs
+func (summary *Summary) BudgetLogs() {
+	for _, file := range summary.logsFiles {
+		path := file.outputPath + file.path
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		logs, skipped := summary.budgetedLogs(file.path, string(content))
+		if skipped {
+			logs = fmt.Sprintf("[... OADP must-gather skipped %d bytes of logs to stay within --max-size ...]\n", len(content))
+		}
+		if len(logs) != len(content) {
+			// TODO permission
+			err = os.WriteFile(path, []byte(logs), 0644)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+		summary.addWrittenSize(path)
+	}
+	summary.logsFiles = nil
+}
+
+// MoveWithinBudget moves files of srcDir to destDir if they fit in size budget, otherwise they are skipped.
+// srcDir is always removed
+func (summary *Summary) MoveWithinBudget(srcDir string, destDir string, what string) error {
+	defer os.RemoveAll(srcDir)
+	var size int64
+	err := filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if sizeBudget != 0 && size > summary.remainingSize() {
+		summary.budgetNotes = append(summary.budgetNotes, fmt.Sprintf("Skipped %s (%s)", what, formatBytes(size)))
+		return nil
+	}
+	err = filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(destDir, relativePath)
+		// TODO permission
+		err = os.MkdirAll(filepath.Dir(destPath), 0777)
+		if err != nil {
+			return err
+		}
+		return os.Rename(path, destPath)
+	})
+	if err != nil {
+		return err
+	}
+	summary.writtenSize += size
+	return nil
+}
+
+func (summary *Summary) ReplaceSizeBudgetSection() {
+	if sizeBudget == 0 {
+		summary.replaces["SIZE_BUDGET"] = fmt.Sprintf("No size budget was set, must-gather wrote %s before this summary", formatBytes(summary.writtenSize))
+		return
+	}
+	summary.replaces["SIZE_BUDGET"] = fmt.Sprintf(
+		"Size budget of %s for this cluster, from %s of `--max-size` split between gathered clusters, must-gather wrote %s before this summary. "+
+			"Summary and CustomResources, including all Backups, Restores and DeleteBackupRequests, are never budgeted\n\n",
+		formatBytes(sizeBudget), formatBytes(totalSizeBudget), formatBytes(summary.writtenSize),
+	)
+	if summary.writtenSize > sizeBudget {
+		summary.replaces["ERRORS"] += fmt.Sprintf(
+			"⚠️ OADP must-gather wrote %s, more than size budget of %s, because summary and CustomResources are never truncated\n\n",
+			formatBytes(summary.writtenSize), formatBytes(sizeBudget),
+		)
+		summary.replaces["SIZE_BUDGET"] += "⚠️ Size budget was exceeded by summary and CustomResources, that are never truncated or skipped\n\n"
+	}
+	if len(summary.budgetNotes) == 0 {
+		summary.replaces["SIZE_BUDGET"] += "Nothing was truncated or skipped"
+		return
+	}
+	summary.replaces["ERRORS"] += "⚠️ Some must-gather output was truncated or skipped to stay within size budget, check size budget section\n\n"
+	summary.replaces["SIZE_BUDGET"] += "⚠️ To stay within size budget\n\n"
+	for _, note := range summary.budgetNotes {
+		summary.replaces["SIZE_BUDGET"] += "- " + note + "\n"
+	}
+}
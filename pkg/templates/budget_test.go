@@ -0,0 +1,57 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateLogs(t *testing.T) {
+	marker := "\n[... OADP must-gather truncated %d bytes of logs to stay within --max-size ...]\n\n"
+	tests := []struct {
+		name  string
+		logs  string
+		limit int64
+		text  string
+	}{
+		{
+			name:  "limit under marker size",
+			logs:  strings.Repeat("line\n", 100),
+			limit: int64(len(marker)),
+			text:  "",
+		},
+		{
+			name:  "cut at newlines",
+			logs:  "first line\nsecond line\n" + strings.Repeat("x", 1000) + "\nlast but one line\nlast line\n",
+			limit: int64(len(marker)) + 20 + 2*30,
+			text:  "first line\nsecond line\n" + fmt.Sprintf(marker, 1001) + "last but one line\nlast line\n",
+		},
+		{
+			name:  "no newline in head or tail",
+			logs:  strings.Repeat("a", 500) + strings.Repeat("b", 500),
+			limit: int64(len(marker)) + 20 + 2*10,
+			text:  strings.Repeat("a", 10) + fmt.Sprintf(marker, 980) + strings.Repeat("b", 10),
+		},
+		{
+			name:  "multi-byte text",
+			logs:  strings.Repeat("é", 500),
+			limit: int64(len(marker)) + 20 + 2*5,
+			text:  "éé" + fmt.Sprintf(marker, 992) + "éé",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text := truncateLogs(test.logs, test.limit)
+			if text != test.text {
+				t.Errorf("truncateLogs() = %q, want %q", text, test.text)
+			}
+			if int64(len(text)) > test.limit {
+				t.Errorf("truncateLogs() returned %d bytes, more than limit %d", len(text), test.limit)
+			}
+			if !utf8.ValidString(text) {
+				t.Errorf("truncateLogs() returned invalid UTF-8 %q", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/manifest"
+)
+
+const clustersSummaryFileName = "oadp-must-gather-clusters-summary.md"
+
+// ClusterOverview is what cross-cluster summary compares of each gathered cluster
+type ClusterOverview struct {
+	Context          string
+	ClusterID        string
+	OpenShiftVersion string
+	// <version> (<namespace>) of each OADP operator installed
+	OADPVersions           []string
+	BackupStorageLocations []velerov1.BackupStorageLocation
+	Backups                []velerov1.Backup
+	// set if gathering cluster failed
+	Error error
+}
+
+func (overview *ClusterOverview) contextText() string {
+	if len(overview.Context) == 0 {
+		return "current context"
+	}
+	return overview.Context
+}
+
+// WriteClustersSummary writes summary comparing OADP versions, BackupStorageLocation targets and Backups of clusters
+func WriteClustersSummary(destDir string, version string, overviews []*ClusterOverview) error {
+	summary := fmt.Sprintf("# OADP must-gather clusters summary version `%s`\n\n## Clusters\n\n", version)
+	summary += "| Context | Cluster ID | OpenShift version | OADP versions | Summary |\n| --- | --- | --- | --- | --- |\n"
+	var gathered []*ClusterOverview
+	oadpVersions := map[string]bool{}
+	for _, overview := range overviews {
+		if overview.Error != nil {
+			summary += fmt.Sprintf("| %s | - | - | - | ❌ %s |\n", overview.contextText(), overview.Error)
+			continue
+		}
+		gathered = append(gathered, overview)
+		versionsText := "-"
+		if len(overview.OADPVersions) != 0 {
+			versionsText = strings.Join(overview.OADPVersions, "<br>")
+		}
+		for _, oadpVersion := range overview.OADPVersions {
+			oadpVersion, _, _ = strings.Cut(oadpVersion, " ")
+			oadpVersions[oadpVersion] = true
+		}
+		summaryPath := fmt.Sprintf("clusters/%s/oadp-must-gather-summary.md", overview.ClusterID)
+		summary += fmt.Sprintf(
+			"| %s | %s | %s | %s | [`summary`](%s) |\n",
+			overview.contextText(), overview.ClusterID, overview.OpenShiftVersion, versionsText, summaryPath,
+		)
+	}
+	if len(oadpVersions) > 1 {
+		summary += fmt.Sprintf(
+			"\n⚠️ Clusters have different OADP versions (%s), cross-cluster restore and migration should use the same OADP version\n",
+			strings.Join(slices.Sorted(maps.Keys(oadpVersions)), ", "),
+		)
+	}
+
+	header := ""
+	separator := ""
+	for _, overview := range gathered {
+		header += " " + overview.contextText() + " |"
+		separator += " --- |"
+	}
+
+	summary += "\n## BackupStorageLocation targets\n\n"
+	// target : cluster ID : BackupStorageLocations text
+	targets := map[string]map[string][]string{}
+	for _, overview := range gathered {
+		for _, bsl := range overview.BackupStorageLocations {
+			target := gather.StorageLocationTarget(&bsl)
+			if targets[target] == nil {
+				targets[target] = map[string][]string{}
+			}
+			targets[target][overview.ClusterID] = append(
+				targets[target][overview.ClusterID],
+				fmt.Sprintf("%s/%s (%s)", bsl.Namespace, bsl.Name, bsl.Status.Phase),
+			)
+		}
+	}
+	if len(targets) == 0 {
+		summary += "❌ No BackupStorageLocation was found in clusters\n"
+	} else {
+		summary += "| target |" + header + "\n| --- |" + separator + "\n"
+		shared := 0
+		for _, target := range slices.Sorted(maps.Keys(targets)) {
+			if len(targets[target]) > 1 {
+				shared++
+			}
+			summary += "| `" + target + "` |"
+			for _, overview := range gathered {
+				locations, ok := targets[target][overview.ClusterID]
+				if !ok {
+					summary += " - |"
+					continue
+				}
+				summary += " " + strings.Join(locations, "<br>") + " |"
+			}
+			summary += "\n"
+		}
+		if shared == 0 && len(gathered) > 1 {
+			summary += "\n⚠️ No BackupStorageLocation target is shared between clusters, cross-cluster restore and migration need one\n"
+		}
+	}
+
+	summary += "\n## Matching Backups\n\nBackups found in more than one cluster, used for cross-cluster restore or migration\n\n"
+	// <namespace>/<name> : cluster ID : Backup text
+	backups := map[string]map[string]string{}
+	for _, overview := range gathered {
+		for _, backup := range overview.Backups {
+			key := backup.Namespace + "/" + backup.Name
+			if backups[key] == nil {
+				backups[key] = map[string]string{}
+			}
+			backups[key][overview.ClusterID] = fmt.Sprintf("%s (%s)", backup.Status.Phase, backup.Spec.StorageLocation)
+		}
+	}
+	matchingText := ""
+	for _, key := range slices.Sorted(maps.Keys(backups)) {
+		if len(backups[key]) < 2 {
+			continue
+		}
+		matchingText += "| " + key + " |"
+		for _, overview := range gathered {
+			backupText, ok := backups[key][overview.ClusterID]
+			if !ok {
+				backupText = "-"
+			}
+			matchingText += " " + backupText + " |"
+		}
+		matchingText += "\n"
+	}
+	if len(matchingText) == 0 {
+		summary += "No Backup was found in more than one cluster\n"
+	} else {
+		summary += "| Backup |" + header + "\n| --- |" + separator + "\n" + matchingText
+	}
+
+	// TODO permission
+	err := os.MkdirAll(destDir, 0777)
+	if err != nil {
+		return err
+	}
+	summaryPath := filepath.Join(destDir, clustersSummaryFileName)
+	// TODO permission
+	err = os.WriteFile(summaryPath, []byte(summary), 0644)
+	if err != nil {
+		return err
+	}
+	manifest.RecordFile(summaryPath, "clusters summary")
+	return nil
+}
@@ -0,0 +1,224 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gvk"
+)
+
+const (
+	olmOwnerLabelPrefix = "operators.coreos.com/"
+	helmReleaseNameKey  = "meta.helm.sh/release-name"
+	allNamespacesText   = "all namespaces"
+)
+
+// managerText returns who installed an object, based on its owners, labels and annotations
+func managerText(object metav1.Object) string {
+	for _, ownerReference := range object.GetOwnerReferences() {
+		if ownerReference.Controller != nil && *ownerReference.Controller {
+			return fmt.Sprintf("%s %s", ownerReference.Kind, ownerReference.Name)
+		}
+	}
+	var olmOwners []string
+	for label := range object.GetLabels() {
+		if strings.HasPrefix(label, olmOwnerLabelPrefix) {
+			olmOwners = append(olmOwners, strings.TrimPrefix(label, olmOwnerLabelPrefix))
+		}
+	}
+	if len(olmOwners) != 0 {
+		slices.Sort(olmOwners)
+		return "OLM " + strings.Join(olmOwners, ", ")
+	}
+	if object.GetLabels()["app.kubernetes.io/managed-by"] == "Helm" {
+		return fmt.Sprintf("Helm release %s", object.GetAnnotations()[helmReleaseNameKey])
+	}
+	return unknownText
+}
+
+// watchedNamespaces returns namespaces watched by an operator, nil if it watches all namespaces
+func watchedNamespaces(csv *operatorsv1alpha1.ClusterServiceVersion) []string {
+	targetNamespaces, ok := csv.Annotations[operatorsv1.OperatorGroupTargetsAnnotationKey]
+	if !ok {
+		return []string{csv.Namespace}
+	}
+	if len(targetNamespaces) == 0 {
+		return nil
+	}
+	return strings.Split(targetNamespaces, ",")
+}
+
+func watchedNamespacesOverlap(a []string, b []string) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	for _, namespace := range a {
+		if slices.Contains(b, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func (summary *Summary) ReplaceInstallationConflictsSection(
+	clusterServiceVersionList *operatorsv1alpha1.ClusterServiceVersionList,
+	veleroDeploymentList *appsv1.DeploymentList,
+	customResourceDefinitionList *apiextensionsv1.CustomResourceDefinitionList,
+) {
+	var oadpCSVs []operatorsv1alpha1.ClusterServiceVersion
+	if clusterServiceVersionList != nil {
+		for _, csv := range clusterServiceVersionList.Items {
+			// OLM copies CSVs of operators watching multiple namespaces to each of them
+			if csv.IsCopied() {
+				continue
+			}
+			if csv.Spec.DisplayName == "OADP Operator" {
+				oadpCSVs = append(oadpCSVs, csv)
+				continue
+			}
+			for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+				if strings.HasSuffix(crd.Name, "."+velerov1.SchemeGroupVersion.Group) {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ Operator **%v** version **%v** in **%v** namespace, which is not OADP, owns Velero CustomResourceDefinition **%v**\n\n",
+						csv.Spec.DisplayName, csv.Spec.Version, csv.Namespace, crd.Name,
+					)
+					break
+				}
+			}
+		}
+	}
+
+	summary.replaces["INSTALLATION_CONFLICTS"] += "#### OADP operators\n\n"
+	if len(oadpCSVs) == 0 {
+		summary.replaces["INSTALLATION_CONFLICTS"] += "❌ No OADP Operator was found installed in the cluster\n\n"
+	} else {
+		summary.replaces["INSTALLATION_CONFLICTS"] += "| Namespace | ClusterServiceVersion | watched namespaces |\n| --- | --- | --- |\n"
+		for index, csv := range oadpCSVs {
+			watched := watchedNamespaces(&csv)
+			watchedText := allNamespacesText
+			if watched != nil {
+				watchedText = strings.Join(watched, "<br>")
+			}
+			summary.replaces["INSTALLATION_CONFLICTS"] += fmt.Sprintf("| %v | %v | %s |\n", csv.Namespace, csv.Name, watchedText)
+			for _, other := range oadpCSVs[index+1:] {
+				if watchedNamespacesOverlap(watched, watchedNamespaces(&other)) {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ OADP operators **%v** in **%v** namespace and **%v** in **%v** namespace watch overlapping namespaces\n\n",
+						csv.Name, csv.Namespace, other.Name, other.Namespace,
+					)
+				}
+			}
+		}
+		summary.replaces["INSTALLATION_CONFLICTS"] += "\n"
+	}
+
+	summary.replaces["INSTALLATION_CONFLICTS"] += "#### Velero deployments\n\n"
+	if veleroDeploymentList == nil || len(veleroDeploymentList.Items) == 0 {
+		summary.replaces["INSTALLATION_CONFLICTS"] += "❌ No Velero Deployment was found in the cluster\n\n"
+	} else {
+		summary.replaces["INSTALLATION_CONFLICTS"] += "| Namespace | Deployment | image | managed by |\n| --- | --- | --- | --- |\n"
+		for _, deployment := range veleroDeploymentList.Items {
+			image := "-"
+			if len(deployment.Spec.Template.Spec.Containers) != 0 {
+				image = deployment.Spec.Template.Spec.Containers[0].Image
+			}
+			manager := managerText(&deployment)
+			managerCell := manager
+			if !strings.HasPrefix(manager, gvk.DataProtectionApplicationGVK.Kind+" ") {
+				managerCell = "❌ " + manager
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ Velero Deployment **%v** in **%v** namespace is not managed by a DataProtectionApplication (managed by %s), it will compete with OADP for Velero CustomResources\n\n",
+					deployment.Name, deployment.Namespace, manager,
+				)
+			}
+			summary.replaces["INSTALLATION_CONFLICTS"] += fmt.Sprintf(
+				"| %v | %v | %v | %s |\n",
+				deployment.Namespace, deployment.Name, image, managerCell,
+			)
+		}
+		summary.replaces["INSTALLATION_CONFLICTS"] += "\n"
+	}
+
+	// CRD name : CRD
+	customResourceDefinitions := map[string]apiextensionsv1.CustomResourceDefinition{}
+	if customResourceDefinitionList != nil {
+		for _, crd := range customResourceDefinitionList.Items {
+			if crd.Spec.Group == velerov1.SchemeGroupVersion.Group || crd.Spec.Group == gvk.DataProtectionApplicationGVK.Group {
+				customResourceDefinitions[crd.Name] = crd
+			}
+		}
+	}
+
+	summary.replaces["INSTALLATION_CONFLICTS"] += "#### Velero and OADP CustomResourceDefinitions\n\n"
+	if len(customResourceDefinitions) == 0 {
+		summary.replaces["INSTALLATION_CONFLICTS"] += "❌ No Velero or OADP CustomResourceDefinition was found in the cluster"
+		return
+	}
+	summary.replaces["INSTALLATION_CONFLICTS"] += "| CustomResourceDefinition | managed by | served versions | storage version |\n| --- | --- | --- | --- |\n"
+	for _, name := range slices.Sorted(maps.Keys(customResourceDefinitions)) {
+		crd := customResourceDefinitions[name]
+		var served []string
+		storage := "-"
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				served = append(served, version.Name)
+			}
+			if version.Storage {
+				storage = version.Name
+			}
+		}
+		manager := managerText(&crd)
+		managerCell := manager
+		if len(oadpCSVs) != 0 && !strings.HasPrefix(manager, "OLM ") {
+			managerCell = "❌ " + manager
+			summary.replaces["ERRORS"] += fmt.Sprintf(
+				"❌ CustomResourceDefinition **%v** is not managed by OLM (managed by %s), it may not match installed OADP version\n\n",
+				crd.Name, manager,
+			)
+		} else if strings.Contains(manager, ", ") {
+			managerCell = "⚠️ " + manager
+			summary.replaces["ERRORS"] += fmt.Sprintf(
+				"⚠️ CustomResourceDefinition **%v** is managed by multiple operators (%s)\n\n",
+				crd.Name, manager,
+			)
+		}
+		summary.replaces["INSTALLATION_CONFLICTS"] += fmt.Sprintf(
+			"| %v | %s | %v | %v |\n",
+			crd.Name, managerCell, strings.Join(served, ", "), storage,
+		)
+	}
+
+	for _, csv := range oadpCSVs {
+		for _, owned := range csv.Spec.CustomResourceDefinitions.Owned {
+			crd, ok := customResourceDefinitions[owned.Name]
+			if !ok {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ CustomResourceDefinition **%v** owned by OADP operator **%v** in **%v** namespace was not found in the cluster\n\n",
+					owned.Name, csv.Name, csv.Namespace,
+				)
+				continue
+			}
+			served := false
+			for _, version := range crd.Spec.Versions {
+				if version.Name == owned.Version && version.Served {
+					served = true
+				}
+			}
+			if !served {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ CustomResourceDefinition **%v** does not serve version **%v** expected by OADP operator **%v** in **%v** namespace\n\n",
+					owned.Name, owned.Version, csv.Name, csv.Namespace,
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/supportmatrix"
+)
+
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+func (summary *Summary) ReplaceCSISnapshotMatrixSection(
+	storageClassList *storagev1.StorageClassList,
+	volumeSnapshotClassList *volumesnapshotv1.VolumeSnapshotClassList,
+	csiDriverList *storagev1.CSIDriverList,
+	persistentVolumeClaimList *corev1.PersistentVolumeClaimList,
+	supportMatrix *supportmatrix.SupportMatrix,
+	oadpOpenShiftVersion string,
+) {
+	if storageClassList == nil || len(storageClassList.Items) == 0 {
+		summary.replaces["CSI_SNAPSHOT_MATRIX"] = "❌ No StorageClass was found in the cluster"
+		return
+	}
+
+	// provisioner : StorageClass names
+	storageClassesByProvisioner := map[string][]string{}
+	provisionerByStorageClass := map[string]string{}
+	// PVCs without storageClassName use default StorageClass, the newest one if more than one is annotated as default
+	var defaultStorageClass *storagev1.StorageClass
+	for index, storageClass := range storageClassList.Items {
+		storageClassesByProvisioner[storageClass.Provisioner] = append(storageClassesByProvisioner[storageClass.Provisioner], storageClass.Name)
+		provisionerByStorageClass[storageClass.Name] = storageClass.Provisioner
+		if storageClass.Annotations[defaultStorageClassAnnotation] == "true" &&
+			(defaultStorageClass == nil || storageClass.CreationTimestamp.After(defaultStorageClass.CreationTimestamp.Time)) {
+			defaultStorageClass = &storageClassList.Items[index]
+		}
+	}
+
+	// provisioner : number of PVCs
+	persistentVolumeClaimsByProvisioner := map[string]int{}
+	if persistentVolumeClaimList != nil {
+		for _, persistentVolumeClaim := range persistentVolumeClaimList.Items {
+			storageClassName := persistentVolumeClaim.Spec.StorageClassName
+			if storageClassName == nil {
+				if defaultStorageClass == nil {
+					continue
+				}
+				storageClassName = &defaultStorageClass.Name
+			}
+			if provisioner, ok := provisionerByStorageClass[*storageClassName]; ok {
+				persistentVolumeClaimsByProvisioner[provisioner]++
+			}
+		}
+	}
+
+	volumeSnapshotClassesByDriver := map[string][]volumesnapshotv1.VolumeSnapshotClass{}
+	if volumeSnapshotClassList != nil {
+		for _, volumeSnapshotClass := range volumeSnapshotClassList.Items {
+			volumeSnapshotClassesByDriver[volumeSnapshotClass.Driver] = append(volumeSnapshotClassesByDriver[volumeSnapshotClass.Driver], volumeSnapshotClass)
+		}
+	}
+
+	var csiDrivers []string
+	if csiDriverList != nil {
+		for _, csiDriver := range csiDriverList.Items {
+			csiDrivers = append(csiDrivers, csiDriver.Name)
+		}
+	}
+	openShiftRelease, knownOpenShiftVersion := supportMatrix.OpenShiftRelease(oadpOpenShiftVersion)
+
+	summary.replaces["CSI_SNAPSHOT_MATRIX"] += fmt.Sprintf(
+		"| Provisioner | StorageClasses | PVCs | VolumeSnapshotClasses | `%s` label | deletionPolicy | CSIDriver | supported in OpenShift %s |\n| --- | --- | --- | --- | --- | --- | --- | --- |\n",
+		velerov1.VolumeSnapshotClassSelectorLabel, oadpOpenShiftVersion,
+	)
+	for _, provisioner := range slices.Sorted(maps.Keys(storageClassesByProvisioner)) {
+		persistentVolumeClaims := persistentVolumeClaimsByProvisioner[provisioner]
+		volumeSnapshotClasses := volumeSnapshotClassesByDriver[provisioner]
+
+		volumeSnapshotClassesText := "❌ none"
+		veleroLabelText := "-"
+		deletionPolicyText := "-"
+		if len(volumeSnapshotClasses) == 0 {
+			if persistentVolumeClaims != 0 {
+				reason := "has **no VolumeSnapshotClass**"
+				if !slices.Contains(csiDrivers, provisioner) {
+					reason = "is **not a CSIDriver**"
+				}
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ **%d** PVCs use provisioner **%v**, which %s, CSI snapshots of them will fail\n\n",
+					persistentVolumeClaims, provisioner, reason,
+				)
+			}
+		} else {
+			var names, labeled, deletionPolicies []string
+			for _, volumeSnapshotClass := range volumeSnapshotClasses {
+				names = append(names, volumeSnapshotClass.Name)
+				deletionPolicy := string(volumeSnapshotClass.DeletionPolicy)
+				if !slices.Contains(deletionPolicies, deletionPolicy) {
+					deletionPolicies = append(deletionPolicies, deletionPolicy)
+				}
+				if volumeSnapshotClass.Labels[velerov1.VolumeSnapshotClassSelectorLabel] == "true" {
+					labeled = append(labeled, volumeSnapshotClass.Name)
+				}
+			}
+			volumeSnapshotClassesText = strings.Join(names, "<br>")
+			deletionPolicyText = strings.Join(deletionPolicies, "<br>")
+			switch len(labeled) {
+			case 0:
+				veleroLabelText = "⚠️ none"
+				if persistentVolumeClaims != 0 {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"⚠️ No VolumeSnapshotClass of provisioner **%v** has **%s** label\n\n",
+						provisioner, velerov1.VolumeSnapshotClassSelectorLabel,
+					)
+				}
+			case 1:
+				veleroLabelText = "✅ " + labeled[0]
+			default:
+				veleroLabelText = "❌ " + strings.Join(labeled, "<br>")
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ Multiple VolumeSnapshotClasses of provisioner **%v** have **%s** label: %v\n\n",
+					provisioner, velerov1.VolumeSnapshotClassSelectorLabel, labeled,
+				)
+			}
+		}
+
+		csiDriverText := "false"
+		supportedText := "-"
+		if slices.Contains(csiDrivers, provisioner) {
+			csiDriverText = "true"
+			if !knownOpenShiftVersion {
+				supportedText = "⚠️ unknown"
+			} else if slices.Contains(openShiftRelease.CSIDrivers, provisioner) {
+				supportedText = "✅ true"
+			} else {
+				supportedText = "⚠️ false"
+			}
+		}
+
+		summary.replaces["CSI_SNAPSHOT_MATRIX"] += fmt.Sprintf(
+			"| %v | %v | %d | %s | %s | %s | %s | %s |\n",
+			provisioner, strings.Join(storageClassesByProvisioner[provisioner], "<br>"), persistentVolumeClaims,
+			volumeSnapshotClassesText, veleroLabelText, deletionPolicyText, csiDriverText, supportedText,
+		)
+	}
+}
@@ -0,0 +1,79 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/velero/pkg/apis/velero/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DataUploads and DataDownloads waiting for the exposer pod longer than this are considered stalled
+	dataMoverStallThreshold = 30 * time.Minute
+)
+
+// status.message set by node-agent when it cancels data mover operations it finds after (re)starting
+var dataMoverNodeAgentRestartMessages = []string{
+	"during the node-agent starting",
+	"Resume InProgress",
+}
+
+func dataMoverProgressText(progress shared.DataMoveOperationProgress) string {
+	if progress.TotalBytes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf(
+		"%s / %s (%d%%)",
+		formatBytes(progress.BytesDone), formatBytes(progress.TotalBytes), progress.BytesDone*100/progress.TotalBytes,
+	)
+}
+
+// dataMoverElapsed returns time since start (or since accepted, then since creation, when start is not set yet) until
+// completion, or until now for operations not finished yet
+func dataMoverElapsed(now time.Time, start *metav1.Time, accepted *metav1.Time, creation metav1.Time, completion *metav1.Time) time.Duration {
+	from := creation.Time
+	if start != nil {
+		from = start.Time
+	} else if accepted != nil {
+		from = accepted.Time
+	}
+	to := now
+	if completion != nil {
+		to = completion.Time
+	}
+	return to.Sub(from)
+}
+
+func dataMoverNodeText(node string, acceptedByNode string) string {
+	if len(node) != 0 {
+		return node
+	}
+	if len(acceptedByNode) != 0 {
+		return acceptedByNode + " (accepted by)"
+	}
+	return "-"
+}
+
+func isCanceledByNodeAgentRestart(message string) bool {
+	for _, restartMessage := range dataMoverNodeAgentRestartMessages {
+		if strings.Contains(message, restartMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
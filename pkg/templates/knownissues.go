@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReplaceKnownIssuesSection matches known issues against status of objects in statusLists, gathered logs were matched when gathered.
+// Hits are added at the top of errors section
+func (summary *Summary) ReplaceKnownIssuesSection(scheme *runtime.Scheme, statusLists ...client.ObjectList) {
+	matcher := summary.knownIssues
+
+	for _, list := range statusLists {
+		objects, err := apimeta.ExtractList(list)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		for _, object := range objects {
+			kinds, _, err := scheme.ObjectKinds(object)
+			if err != nil || len(kinds) == 0 {
+				continue
+			}
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			status, err := json.Marshal(content["status"])
+			if err != nil {
+				continue
+			}
+			metadata, err := apimeta.Accessor(object)
+			if err != nil {
+				continue
+			}
+			matcher.MatchStatus(kinds[0].Kind, metadata.GetNamespace(), metadata.GetName(), string(status))
+		}
+	}
+
+	knownIssuesText := ""
+	for _, hit := range matcher.Hits() {
+		knownIssuesText += fmt.Sprintf(
+			"🔎 Known issue **%s**: %s\n\nRemediation: %s For more information, check %s\n\n```\n%s\n```\n\n",
+			hit.Issue.ID, hit.Issue.Title, hit.Issue.Remediation, hit.Issue.Link, strings.Join(hit.Evidence, "\n"),
+		)
+	}
+	summary.replaces["ERRORS"] = knownIssuesText + summary.replaces["ERRORS"]
+}
@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/logs"
+)
+
+const topLogErrorsCount = 20
+
+func seenText(seen time.Time) string {
+	if seen.IsZero() {
+		return "-"
+	}
+	return seen.UTC().Format(time.RFC3339)
+}
+
+// addLogs groups errors and matches known issues of a gathered log, source identifies the log
+func (summary *Summary) addLogs(source string, text string) {
+	summary.logErrors.Add(source, logs.Parse(text), "error")
+	summary.knownIssues.MatchLogs(source, text)
+}
+
+// ReplaceTopLogErrorsSection shows errors of gathered Backup, Restore and node-agent logs grouped by signature
+func (summary *Summary) ReplaceTopLogErrorsSection() {
+	top := summary.logErrors.Top(topLogErrorsCount)
+	if len(top) == 0 {
+		summary.replaces["TOP_LOG_ERRORS"] = "No error was found in gathered Backup, Restore and node-agent logs"
+		return
+	}
+	summary.replaces["TOP_LOG_ERRORS"] += "| count | signature | first seen | last seen | affected resources | logs |\n| --- | --- | --- | --- | --- | --- |\n"
+	for _, cluster := range top {
+		resources := "-"
+		if len(cluster.Resources) != 0 {
+			resources = strings.Join(cluster.Resources, "<br>")
+		}
+		summary.replaces["TOP_LOG_ERRORS"] += fmt.Sprintf(
+			"| %d | `%s` | %s | %s | %s | %s |\n",
+			cluster.Count, strings.ReplaceAll(cluster.Signature, "|", "\\|"), seenText(cluster.FirstSeen), seenText(cluster.LastSeen),
+			resources, strings.Join(cluster.Sources, "<br>"),
+		)
+	}
+}
@@ -0,0 +1,230 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	oadpv1alpha1 "github.com/openshift/oadp-operator/api/v1alpha1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// container waiting/terminated messages when image has no manifest for node architecture
+var imageArchitectureErrorMessages = []string{
+	"no matching manifest",
+	"exec format error",
+}
+
+func nodeAgentPodConfig(dataProtectionApplication *oadpv1alpha1.DataProtectionApplication) (bool, *oadpv1alpha1.PodConfig) {
+	configuration := dataProtectionApplication.Spec.Configuration
+	if configuration == nil {
+		return false, nil
+	}
+	if configuration.NodeAgent != nil && configuration.NodeAgent.Enable != nil && *configuration.NodeAgent.Enable {
+		return true, configuration.NodeAgent.PodConfig
+	}
+	if configuration.Restic != nil && configuration.Restic.Enable != nil && *configuration.Restic.Enable {
+		return true, configuration.Restic.PodConfig
+	}
+	return false, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// untoleratedTaints returns node taints that prevent scheduling and are not tolerated
+func untoleratedTaints(node *corev1.Node, tolerations []corev1.Toleration) []string {
+	var taints []string
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		// DaemonSet pods tolerate these taints by default
+		if strings.HasPrefix(taint.Key, "node.kubernetes.io/") {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		}
+	}
+	return taints
+}
+
+func imageArchitectureError(pod *corev1.Pod) string {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		messages := []string{}
+		if containerStatus.State.Waiting != nil {
+			messages = append(messages, containerStatus.State.Waiting.Message)
+		}
+		if containerStatus.LastTerminationState.Terminated != nil {
+			messages = append(messages, containerStatus.LastTerminationState.Terminated.Message)
+		}
+		for _, message := range messages {
+			for _, architectureMessage := range imageArchitectureErrorMessages {
+				if strings.Contains(message, architectureMessage) {
+					return message
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (summary *Summary) ReplaceNodeAgentCoverageSection(
+	nodeList *corev1.NodeList,
+	dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList,
+	nodeAgentPodList *corev1.PodList,
+	podVolumeBackupList *velerov1.PodVolumeBackupList,
+	backedUpPodList *corev1.PodList,
+) {
+	if nodeList == nil || len(nodeList.Items) == 0 || dataProtectionApplicationList == nil || len(dataProtectionApplicationList.Items) == 0 {
+		summary.replaces["NODE_AGENT_COVERAGE"] = "❌ No Node or DataProtectionApplication was found in the cluster"
+		return
+	}
+
+	// nodes running pods with PVCs that are backed up
+	nodesWithBackedUpVolumes := map[string]bool{}
+	if podVolumeBackupList != nil {
+		for _, podVolumeBackup := range podVolumeBackupList.Items {
+			nodesWithBackedUpVolumes[podVolumeBackup.Spec.Node] = true
+		}
+	}
+	if backedUpPodList != nil {
+		for _, pod := range backedUpPodList.Items {
+			if len(pod.Spec.NodeName) == 0 {
+				continue
+			}
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					nodesWithBackedUpVolumes[pod.Spec.NodeName] = true
+					break
+				}
+			}
+		}
+	}
+
+	architectures := map[string]bool{}
+	for _, node := range nodeList.Items {
+		architectures[node.Status.NodeInfo.Architecture] = true
+	}
+
+	for _, dataProtectionApplication := range dataProtectionApplicationList.Items {
+		enabled, podConfig := nodeAgentPodConfig(&dataProtectionApplication)
+		if !enabled {
+			summary.replaces["NODE_AGENT_COVERAGE"] += fmt.Sprintf(
+				"DataProtectionApplication **%v** in **%v** namespace does not enable node-agent\n\n",
+				dataProtectionApplication.Name, dataProtectionApplication.Namespace,
+			)
+			continue
+		}
+		var nodeSelector labels.Selector = labels.Everything()
+		var tolerations []corev1.Toleration
+		if podConfig != nil {
+			if len(podConfig.NodeSelector) != 0 {
+				nodeSelector = labels.SelectorFromSet(podConfig.NodeSelector)
+			}
+			tolerations = podConfig.Tolerations
+		}
+
+		nodeSelectorText := "none"
+		if !nodeSelector.Empty() {
+			nodeSelectorText = "`" + nodeSelector.String() + "`"
+		}
+		summary.replaces["NODE_AGENT_COVERAGE"] += fmt.Sprintf(
+			"DataProtectionApplication **%v** in **%v** namespace, nodeSelector %s, **%d** tolerations\n\n",
+			dataProtectionApplication.Name, dataProtectionApplication.Namespace, nodeSelectorText, len(tolerations),
+		)
+		summary.replaces["NODE_AGENT_COVERAGE"] += "| Node | architecture | schedulable | matches nodeSelector | untolerated taints | backed up volumes | node-agent pod |\n| --- | --- | --- | --- | --- | --- | --- |\n"
+		for _, node := range nodeList.Items {
+			schedulableText := "✅ true"
+			if node.Spec.Unschedulable {
+				schedulableText = "⚠️ false"
+			}
+			matchesSelector := nodeSelector.Matches(labels.Set(node.Labels))
+			taints := untoleratedTaints(&node, tolerations)
+			expected := matchesSelector && len(taints) == 0
+
+			var nodeAgentPod *corev1.Pod
+			if nodeAgentPodList != nil {
+				for _, pod := range nodeAgentPodList.Items {
+					if pod.Namespace == dataProtectionApplication.Namespace && pod.Spec.NodeName == node.Name {
+						nodeAgentPod = &pod
+						break
+					}
+				}
+			}
+			nodeAgentText := "-"
+			nodeAgentReady := false
+			if nodeAgentPod != nil {
+				nodeAgentReady = isPodReady(nodeAgentPod)
+				if nodeAgentReady {
+					nodeAgentText = fmt.Sprintf("✅ %s ready", nodeAgentPod.Name)
+				} else {
+					nodeAgentText = fmt.Sprintf("❌ %s not ready", nodeAgentPod.Name)
+				}
+				if message := imageArchitectureError(nodeAgentPod); len(message) != 0 {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ node-agent pod **%v** in **%v** namespace can not run on **%v** node with **%v** architecture, image may lack a manifest for it: %s\n\n",
+						nodeAgentPod.Name, nodeAgentPod.Namespace, node.Name, node.Status.NodeInfo.Architecture, message,
+					)
+				}
+			} else if expected {
+				nodeAgentText = "⚠️ missing"
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ No node-agent pod from **%v** namespace is running on **%v** node, which matches DataProtectionApplication **%v** nodeSelector and tolerations\n\n",
+					dataProtectionApplication.Namespace, node.Name, dataProtectionApplication.Name,
+				)
+			}
+
+			backedUpVolumesText := "false"
+			if nodesWithBackedUpVolumes[node.Name] {
+				backedUpVolumesText = "true"
+				if !nodeAgentReady {
+					backedUpVolumesText = "❌ true"
+					reason := "no ready node-agent pod"
+					if !matchesSelector {
+						reason += ", node does not match nodeSelector"
+					}
+					if len(taints) != 0 {
+						reason += fmt.Sprintf(", node has untolerated taints %v", taints)
+					}
+					if len(architectures) > 1 {
+						reason += fmt.Sprintf(", node architecture is %v", node.Status.NodeInfo.Architecture)
+					}
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ **%v** node hosts backed up PVCs, but has %s from DataProtectionApplication **%v** in **%v** namespace\n\n",
+						node.Name, reason, dataProtectionApplication.Name, dataProtectionApplication.Namespace,
+					)
+				}
+			}
+
+			matchesSelectorText := "✅ true"
+			if !matchesSelector {
+				matchesSelectorText = "false"
+			}
+			taintsText := "-"
+			if len(taints) != 0 {
+				taintsText = strings.Join(taints, "<br>")
+			}
+			summary.replaces["NODE_AGENT_COVERAGE"] += fmt.Sprintf(
+				"| %v | %v | %s | %s | %s | %s | %s |\n",
+				node.Name, node.Status.NodeInfo.Architecture, schedulableText, matchesSelectorText, taintsText, backedUpVolumesText, nodeAgentText,
+			)
+		}
+		summary.replaces["NODE_AGENT_COVERAGE"] += "\n"
+	}
+}
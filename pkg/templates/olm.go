@@ -0,0 +1,255 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gvk"
+)
+
+// catalogSourceReadyState is CatalogSource gRPC connection state when it is serving content
+const catalogSourceReadyState = "READY"
+
+// olmYAML writes objects of a namespace to namespaces/<namespace>/operators.coreos.com/<resource>/<resource>.yaml
+func (summary *Summary) olmYAML(outputPath string, namespace string, resource string, objects []runtime.Object) string {
+	if len(objects) == 0 {
+		return ""
+	}
+	list := &corev1.List{}
+	list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
+	for _, object := range objects {
+		list.Items = append(list.Items, runtime.RawExtension{Object: object})
+	}
+	file := fmt.Sprintf("namespaces/%s/operators.coreos.com/%s/%s.yaml", namespace, resource, resource)
+	summary.createYAML(outputPath, file, list)
+	return fmt.Sprintf("[`%s.yaml`](%s)", resource, file)
+}
+
+func (summary *Summary) ReplaceOLMSection(
+	outputPath string,
+	importantCSVsByNamespace map[string][]operatorsv1alpha1.ClusterServiceVersion,
+	subscriptionList *operatorsv1alpha1.SubscriptionList,
+	installPlanList *operatorsv1alpha1.InstallPlanList,
+	operatorGroupList *operatorsv1.OperatorGroupList,
+	catalogSourceList *operatorsv1alpha1.CatalogSourceList,
+) {
+	if len(importantCSVsByNamespace) == 0 {
+		summary.replaces["OLM"] = "❌ No OADP Operator was found installed in the cluster"
+		return
+	}
+
+	summary.replaces["OLM"] += "#### ClusterServiceVersions\n\n| Namespace | Name | status.phase | status.reason | status.message |\n| --- | --- | --- | --- | --- |\n"
+	for _, namespace := range slices.Sorted(maps.Keys(importantCSVsByNamespace)) {
+		for _, csv := range importantCSVsByNamespace[namespace] {
+			phase := string(csv.Status.Phase)
+			switch csv.Status.Phase {
+			case operatorsv1alpha1.CSVPhaseSucceeded:
+				phase = "✅ " + phase
+			case operatorsv1alpha1.CSVPhaseFailed:
+				phase = "❌ " + phase
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ ClusterServiceVersion **%v** in **%v** namespace is in **%s** phase (%s): %s\n\n",
+					csv.Name, namespace, csv.Status.Phase, csv.Status.Reason, csv.Status.Message,
+				)
+			default:
+				phase = "⚠️ " + phase
+			}
+			summary.replaces["OLM"] += fmt.Sprintf(
+				"| %v | %v | %s | %v | %v |\n",
+				namespace, csv.Name, phase, csv.Status.Reason, csv.Status.Message,
+			)
+		}
+	}
+	summary.replaces["OLM"] += "\n"
+
+	// namespace : objects
+	subscriptionsByNamespace := map[string][]runtime.Object{}
+	installPlansByNamespace := map[string][]runtime.Object{}
+	operatorGroupsByNamespace := map[string][]runtime.Object{}
+	catalogSourcesByNamespace := map[string][]runtime.Object{}
+	// <namespace>/<name> : CatalogSource
+	catalogSources := map[string]operatorsv1alpha1.CatalogSource{}
+
+	summary.replaces["OLM"] += "#### Subscriptions\n\n"
+	if subscriptionList == nil || len(subscriptionList.Items) == 0 {
+		summary.replaces["OLM"] += "❌ No Subscription was found in OADP namespaces\n\n"
+	} else {
+		if catalogSourceList != nil {
+			for _, catalogSource := range catalogSourceList.Items {
+				catalogSources[catalogSource.Namespace+"/"+catalogSource.Name] = catalogSource
+			}
+		}
+		summary.replaces["OLM"] += "| Namespace | Name | spec.name | spec.channel | spec.installPlanApproval | spec.source | status.installedCSV | status.currentCSV | status.state |\n| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n"
+		for _, subscription := range subscriptionList.Items {
+			subscription.GetObjectKind().SetGroupVersionKind(gvk.SubscriptionGVK)
+			subscriptionsByNamespace[subscription.Namespace] = append(subscriptionsByNamespace[subscription.Namespace], &subscription)
+
+			state := subscription.Status.State
+			stateText := string(state)
+			switch state {
+			case operatorsv1alpha1.SubscriptionStateAtLatest:
+				stateText = "✅ " + stateText
+			case operatorsv1alpha1.SubscriptionStateFailed:
+				stateText = "❌ " + stateText
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ Subscription **%v** in **%v** namespace failed to upgrade from **%v** to **%v**\n\n",
+					subscription.Name, subscription.Namespace, subscription.Status.InstalledCSV, subscription.Status.CurrentCSV,
+				)
+			case operatorsv1alpha1.SubscriptionStateUpgradeAvailable, operatorsv1alpha1.SubscriptionStateUpgradePending:
+				stateText = "⚠️ " + stateText
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ Subscription **%v** in **%v** namespace has pending upgrade from **%v** to **%v** (installPlanApproval %s)\n\n",
+					subscription.Name, subscription.Namespace, subscription.Status.InstalledCSV, subscription.Status.CurrentCSV, subscription.Spec.InstallPlanApproval,
+				)
+			default:
+				stateText = "⚠️ " + stateText
+			}
+
+			sourceNamespace := subscription.Spec.CatalogSourceNamespace
+			source := sourceNamespace + "/" + subscription.Spec.CatalogSource
+			sourceText := source
+			catalogSource, ok := catalogSources[source]
+			if !ok {
+				sourceText = "❌ " + source
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ CatalogSource **%v** referenced by Subscription **%v** in **%v** namespace was not found in the cluster\n\n",
+					source, subscription.Name, subscription.Namespace,
+				)
+			} else if !slices.ContainsFunc(catalogSourcesByNamespace[sourceNamespace], func(object runtime.Object) bool {
+				return object.(*operatorsv1alpha1.CatalogSource).Name == catalogSource.Name
+			}) {
+				catalogSource.GetObjectKind().SetGroupVersionKind(gvk.CatalogSourceGVK)
+				catalogSourcesByNamespace[sourceNamespace] = append(catalogSourcesByNamespace[sourceNamespace], &catalogSource)
+			}
+
+			summary.replaces["OLM"] += fmt.Sprintf(
+				"| %v | %v | %v | %v | %v | %s | %v | %v | %s |\n",
+				subscription.Namespace, subscription.Name, subscription.Spec.Package, subscription.Spec.Channel,
+				subscription.Spec.InstallPlanApproval, sourceText, subscription.Status.InstalledCSV, subscription.Status.CurrentCSV, stateText,
+			)
+		}
+		summary.replaces["OLM"] += "\n"
+	}
+
+	summary.replaces["OLM"] += "#### InstallPlans\n\n"
+	if installPlanList == nil || len(installPlanList.Items) == 0 {
+		summary.replaces["OLM"] += "No InstallPlan was found in OADP namespaces\n\n"
+	} else {
+		summary.replaces["OLM"] += "| Namespace | Name | spec.clusterServiceVersionNames | spec.approval | spec.approved | status.phase |\n| --- | --- | --- | --- | --- | --- |\n"
+		for _, installPlan := range installPlanList.Items {
+			installPlan.GetObjectKind().SetGroupVersionKind(gvk.InstallPlanGVK)
+			installPlansByNamespace[installPlan.Namespace] = append(installPlansByNamespace[installPlan.Namespace], &installPlan)
+
+			phase := string(installPlan.Status.Phase)
+			switch installPlan.Status.Phase {
+			case operatorsv1alpha1.InstallPlanPhaseComplete:
+				phase = "✅ " + phase
+			case operatorsv1alpha1.InstallPlanPhaseFailed:
+				phase = "❌ " + phase
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ InstallPlan **%v** in **%v** namespace for %v failed: %s\n\n",
+					installPlan.Name, installPlan.Namespace, installPlan.Spec.ClusterServiceVersionNames, installPlan.Status.Message,
+				)
+			case operatorsv1alpha1.InstallPlanPhaseRequiresApproval:
+				phase = "⚠️ " + phase
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ InstallPlan **%v** in **%v** namespace for %v is waiting for manual approval\n\n",
+					installPlan.Name, installPlan.Namespace, installPlan.Spec.ClusterServiceVersionNames,
+				)
+			default:
+				phase = "⚠️ " + phase
+			}
+			summary.replaces["OLM"] += fmt.Sprintf(
+				"| %v | %v | %v | %v | %v | %s |\n",
+				installPlan.Namespace, installPlan.Name, strings.Join(installPlan.Spec.ClusterServiceVersionNames, "<br>"),
+				installPlan.Spec.Approval, installPlan.Spec.Approved, phase,
+			)
+		}
+		summary.replaces["OLM"] += "\n"
+	}
+
+	summary.replaces["OLM"] += "#### OperatorGroups\n\n"
+	if operatorGroupList == nil || len(operatorGroupList.Items) == 0 {
+		summary.replaces["OLM"] += "❌ No OperatorGroup was found in OADP namespaces\n\n"
+	} else {
+		summary.replaces["OLM"] += "| Namespace | Name | status.namespaces |\n| --- | --- | --- |\n"
+		for _, operatorGroup := range operatorGroupList.Items {
+			operatorGroup.GetObjectKind().SetGroupVersionKind(gvk.OperatorGroupGVK)
+			operatorGroupsByNamespace[operatorGroup.Namespace] = append(operatorGroupsByNamespace[operatorGroup.Namespace], &operatorGroup)
+
+			namespacesText := strings.Join(operatorGroup.Status.Namespaces, "<br>")
+			if slices.Contains(operatorGroup.Status.Namespaces, "") {
+				namespacesText = allNamespacesText
+			}
+			summary.replaces["OLM"] += fmt.Sprintf("| %v | %v | %s |\n", operatorGroup.Namespace, operatorGroup.Name, namespacesText)
+		}
+		summary.replaces["OLM"] += "\n"
+		for _, namespace := range slices.Sorted(maps.Keys(operatorGroupsByNamespace)) {
+			if len(operatorGroupsByNamespace[namespace]) > 1 {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ **%d** OperatorGroups in **%v** namespace, OLM does not install operators in namespaces with more than one OperatorGroup\n\n",
+					len(operatorGroupsByNamespace[namespace]), namespace,
+				)
+			}
+		}
+	}
+
+	summary.replaces["OLM"] += "#### CatalogSources\n\n"
+	if len(catalogSourcesByNamespace) == 0 {
+		summary.replaces["OLM"] += "❌ No CatalogSource referenced by OADP Subscriptions was found in the cluster\n\n"
+	} else {
+		summary.replaces["OLM"] += "| Namespace | Name | spec.image | spec.publisher | connection state |\n| --- | --- | --- | --- | --- |\n"
+		for _, namespace := range slices.Sorted(maps.Keys(catalogSourcesByNamespace)) {
+			for _, object := range catalogSourcesByNamespace[namespace] {
+				catalogSource := object.(*operatorsv1alpha1.CatalogSource)
+				stateText := "⚠️ unknown"
+				if catalogSource.Status.GRPCConnectionState != nil {
+					state := catalogSource.Status.GRPCConnectionState.LastObservedState
+					stateText = "✅ " + state
+					if state != catalogSourceReadyState {
+						stateText = "❌ " + state
+						summary.replaces["ERRORS"] += fmt.Sprintf(
+							"❌ CatalogSource **%v** in **%v** namespace is in **%v** state, OADP upgrades from it will not happen\n\n",
+							catalogSource.Name, namespace, state,
+						)
+					}
+				}
+				summary.replaces["OLM"] += fmt.Sprintf(
+					"| %v | %v | %v | %v | %s |\n",
+					namespace, catalogSource.Name, catalogSource.Spec.Image, catalogSource.Spec.Publisher, stateText,
+				)
+			}
+		}
+		summary.replaces["OLM"] += "\n"
+	}
+
+	namespaces := slices.Concat(
+		slices.Collect(maps.Keys(subscriptionsByNamespace)),
+		slices.Collect(maps.Keys(installPlansByNamespace)),
+		slices.Collect(maps.Keys(operatorGroupsByNamespace)),
+		slices.Collect(maps.Keys(catalogSourcesByNamespace)),
+	)
+	slices.Sort(namespaces)
+	for _, namespace := range slices.Compact(namespaces) {
+		var links []string
+		for resource, objects := range map[string][]runtime.Object{
+			"subscriptions":  subscriptionsByNamespace[namespace],
+			"installplans":   installPlansByNamespace[namespace],
+			"operatorgroups": operatorGroupsByNamespace[namespace],
+			"catalogsources": catalogSourcesByNamespace[namespace],
+		} {
+			if link := summary.olmYAML(outputPath, namespace, resource, objects); len(link) != 0 {
+				links = append(links, link)
+			}
+		}
+		slices.Sort(links)
+		summary.replaces["OLM"] += fmt.Sprintf("For more information about **%v** namespace, check %s\n\n", namespace, strings.Join(links, ", "))
+	}
+}
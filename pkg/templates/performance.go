@@ -0,0 +1,230 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	velerov2alpha1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v2alpha1"
+	"github.com/vmware-tanzu/velero/pkg/label"
+)
+
+const (
+	// runs slower than this factor of their baseline (median) are flagged
+	slowRunFactor = 2
+	// minimum finished runs for a baseline
+	baselineMinimumRuns = 5
+	trendWindow         = 7 * 24 * time.Hour
+)
+
+type finishedRun struct {
+	namespace string
+	name      string
+	start     time.Time
+	duration  time.Duration
+	items     int
+	bytes     int64
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, percent int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (percent*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func median(durations []time.Duration) time.Duration {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	return percentile(sorted, 50)
+}
+
+func throughputText(bytes int64, duration time.Duration) string {
+	if bytes == 0 || duration == 0 {
+		return "-"
+	}
+	return formatBytes(int64(float64(bytes)/duration.Seconds())) + "/s"
+}
+
+// runsTable writes percentiles and trend of each group of runs and returns findings about slow runs
+func runsTable(kind string, dimension string, runsByGroup map[string][]finishedRun, now time.Time) (string, string) {
+	table := fmt.Sprintf(
+		"| %s | finished runs | p50 | p90 | max | median last 7 days | median before | trend |\n| --- | --- | --- | --- | --- | --- | --- | --- |\n",
+		dimension,
+	)
+	findings := ""
+	for _, group := range slices.Sorted(maps.Keys(runsByGroup)) {
+		runs := runsByGroup[group]
+		var durations, recent, previous []time.Duration
+		for _, run := range runs {
+			durations = append(durations, run.duration)
+			if now.Sub(run.start) <= trendWindow {
+				recent = append(recent, run.duration)
+			} else {
+				previous = append(previous, run.duration)
+			}
+		}
+		sorted := slices.Clone(durations)
+		slices.Sort(sorted)
+		baseline := percentile(sorted, 50)
+		// Backups without schedule back up different resources, so they have no comparable baseline
+		manualBackups := strings.HasSuffix(group, "/"+noScheduleText)
+
+		recentText, previousText, trendText := "-", "-", "-"
+		if len(recent) != 0 {
+			recentText = median(recent).Round(time.Second).String()
+		}
+		if len(previous) != 0 {
+			previousText = median(previous).Round(time.Second).String()
+		}
+		if len(recent) != 0 && len(previous) != 0 && median(previous) != 0 {
+			change := float64(median(recent)-median(previous)) / float64(median(previous)) * 100
+			trendText = fmt.Sprintf("%+.0f%%", change)
+			if median(recent) > slowRunFactor*median(previous) && !manualBackups {
+				trendText = "⚠️ " + trendText
+				findings += fmt.Sprintf(
+					"⚠️ %ss of **%v** in the last 7 days take **%s** (median), slower than **%s** before\n\n",
+					kind, group, median(recent).Round(time.Second), median(previous).Round(time.Second),
+				)
+			}
+		}
+		table += fmt.Sprintf(
+			"| %v | %d | %s | %s | %s | %s | %s | %s |\n",
+			group, len(runs), baseline.Round(time.Second), percentile(sorted, 90).Round(time.Second), sorted[len(sorted)-1].Round(time.Second),
+			recentText, previousText, trendText,
+		)
+
+		if len(runs) < baselineMinimumRuns || manualBackups {
+			continue
+		}
+		var items []int
+		var bytes []int64
+		for _, run := range runs {
+			items = append(items, run.items)
+			bytes = append(bytes, run.bytes)
+		}
+		slices.Sort(items)
+		slices.Sort(bytes)
+		baselineItems := items[(len(items)-1)/2]
+		baselineBytes := bytes[(len(bytes)-1)/2]
+		for _, run := range runs {
+			if run.duration <= slowRunFactor*baseline {
+				continue
+			}
+			correlation := "item count and volume bytes are close to baseline, check logs and cluster load"
+			if run.items > slowRunFactor*baselineItems || run.bytes > slowRunFactor*baselineBytes {
+				correlation = "more items or volume bytes than baseline"
+			}
+			findings += fmt.Sprintf(
+				"⚠️ %s **%v** in **%v** namespace took **%s**, baseline of **%v** is **%s** (items %d vs %d, volume bytes %s vs %s, throughput %s): %s\n\n",
+				kind, run.name, run.namespace, run.duration.Round(time.Second), group, baseline.Round(time.Second),
+				run.items, baselineItems, formatBytes(run.bytes), formatBytes(baselineBytes), throughputText(run.bytes, run.duration), correlation,
+			)
+		}
+	}
+	return table + "\n", findings
+}
+
+func (summary *Summary) ReplacePerformanceSection(
+	backupList *velerov1.BackupList,
+	restoreList *velerov1.RestoreList,
+	podVolumeBackupList *velerov1.PodVolumeBackupList,
+	podVolumeRestoreList *velerov1.PodVolumeRestoreList,
+	dataUploadList *velerov2alpha1.DataUploadList,
+	dataDownloadList *velerov2alpha1.DataDownloadList,
+) {
+	now := time.Now()
+
+	// <namespace>/<backup name label> : volume bytes
+	backupVolumeBytes := map[string]int64{}
+	// <namespace>/<restore name label> : volume bytes
+	restoreVolumeBytes := map[string]int64{}
+	if podVolumeBackupList != nil {
+		for _, podVolumeBackup := range podVolumeBackupList.Items {
+			backupVolumeBytes[podVolumeBackup.Namespace+"/"+podVolumeBackup.Labels[velerov1.BackupNameLabel]] += podVolumeBackup.Status.Progress.BytesDone
+		}
+	}
+	if dataUploadList != nil {
+		for _, dataUpload := range dataUploadList.Items {
+			backupVolumeBytes[dataUpload.Namespace+"/"+dataUpload.Labels[velerov1.BackupNameLabel]] += dataUpload.Status.Progress.BytesDone
+		}
+	}
+	if podVolumeRestoreList != nil {
+		for _, podVolumeRestore := range podVolumeRestoreList.Items {
+			restoreVolumeBytes[podVolumeRestore.Namespace+"/"+podVolumeRestore.Labels[velerov1.RestoreNameLabel]] += podVolumeRestore.Status.Progress.BytesDone
+		}
+	}
+	if dataDownloadList != nil {
+		for _, dataDownload := range dataDownloadList.Items {
+			restoreVolumeBytes[dataDownload.Namespace+"/"+dataDownload.Labels[velerov1.RestoreNameLabel]] += dataDownload.Status.Progress.BytesDone
+		}
+	}
+
+	backupRunsBySchedule := map[string][]finishedRun{}
+	if backupList != nil {
+		for _, backup := range backupList.Items {
+			if backup.Status.StartTimestamp == nil || backup.Status.CompletionTimestamp == nil {
+				continue
+			}
+			schedule := backup.Labels[velerov1.ScheduleNameLabel]
+			if len(schedule) == 0 {
+				schedule = noScheduleText
+			}
+			items := 0
+			if backup.Status.Progress != nil {
+				items = backup.Status.Progress.ItemsBackedUp
+			}
+			backupRunsBySchedule[backup.Namespace+"/"+schedule] = append(backupRunsBySchedule[backup.Namespace+"/"+schedule], finishedRun{
+				namespace: backup.Namespace,
+				name:      backup.Name,
+				start:     backup.Status.StartTimestamp.Time,
+				duration:  backup.Status.CompletionTimestamp.Sub(backup.Status.StartTimestamp.Time),
+				items:     items,
+				bytes:     backupVolumeBytes[backup.Namespace+"/"+label.GetValidName(backup.Name)],
+			})
+		}
+	}
+	if len(backupRunsBySchedule) == 0 {
+		summary.replaces["PERFORMANCE"] += "❌ No finished Backup was found in the cluster\n\n"
+	} else {
+		table, findings := runsTable("Backup", "Schedule", backupRunsBySchedule, now)
+		summary.replaces["PERFORMANCE"] += "Backup durations\n\n" + table
+		summary.replaces["ERRORS"] += findings
+	}
+
+	restoreRunsByNamespace := map[string][]finishedRun{}
+	if restoreList != nil {
+		for _, restore := range restoreList.Items {
+			if restore.Status.StartTimestamp == nil || restore.Status.CompletionTimestamp == nil {
+				continue
+			}
+			items := 0
+			if restore.Status.Progress != nil {
+				items = restore.Status.Progress.ItemsRestored
+			}
+			restoreRunsByNamespace[restore.Namespace] = append(restoreRunsByNamespace[restore.Namespace], finishedRun{
+				namespace: restore.Namespace,
+				name:      restore.Name,
+				start:     restore.Status.StartTimestamp.Time,
+				duration:  restore.Status.CompletionTimestamp.Sub(restore.Status.StartTimestamp.Time),
+				items:     items,
+				bytes:     restoreVolumeBytes[restore.Namespace+"/"+label.GetValidName(restore.Name)],
+			})
+		}
+	}
+	if len(restoreRunsByNamespace) == 0 {
+		summary.replaces["PERFORMANCE"] += "❌ No finished Restore was found in the cluster"
+	} else {
+		table, findings := runsTable("Restore", "Namespace", restoreRunsByNamespace, now)
+		summary.replaces["PERFORMANCE"] += "Restore durations\n\n" + table
+		summary.replaces["ERRORS"] += findings
+	}
+}
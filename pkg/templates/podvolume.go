@@ -0,0 +1,334 @@
+package templates
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+)
+
+const (
+	nodeAgentLogExcerptLines = 20
+	unknownText              = "unknown"
+)
+
+type podVolumeAggregate struct {
+	total     int
+	completed int
+	failed    int
+	other     int
+	bytesDone int64
+	duration  time.Duration
+}
+
+func (aggregate *podVolumeAggregate) add(phase string, bytesDone int64, start *time.Time, completion *time.Time) {
+	aggregate.total++
+	switch phase {
+	case string(velerov1.PodVolumeBackupPhaseCompleted):
+		aggregate.completed++
+	case string(velerov1.PodVolumeBackupPhaseFailed):
+		aggregate.failed++
+	default:
+		aggregate.other++
+	}
+	aggregate.bytesDone += bytesDone
+	if start != nil && completion != nil {
+		aggregate.duration += completion.Sub(*start)
+	}
+}
+
+func podVolumeAggregateTable(dimension string, aggregates map[string]*podVolumeAggregate) string {
+	table := fmt.Sprintf(
+		"| %s | total | Completed | Failed | other phases | bytes done | total duration |\n| --- | --- | --- | --- | --- | --- | --- |\n",
+		dimension,
+	)
+	for _, key := range slices.Sorted(maps.Keys(aggregates)) {
+		aggregate := aggregates[key]
+		failedText := "0"
+		if aggregate.failed != 0 {
+			failedText = fmt.Sprintf("❌ %d", aggregate.failed)
+		}
+		otherText := "0"
+		if aggregate.other != 0 {
+			otherText = fmt.Sprintf("⚠️ %d", aggregate.other)
+		}
+		table += fmt.Sprintf(
+			"| %v | %d | %d | %s | %s | %s | %s |\n",
+			key, aggregate.total, aggregate.completed, failedText, otherText, formatBytes(aggregate.bytesDone), aggregate.duration.Round(time.Second),
+		)
+	}
+	return table + "\n"
+}
+
+func addToAggregate(aggregates map[string]*podVolumeAggregate, key string) *podVolumeAggregate {
+	if len(key) == 0 {
+		key = unknownText
+	}
+	if aggregates[key] == nil {
+		aggregates[key] = &podVolumeAggregate{}
+	}
+	return aggregates[key]
+}
+
+// podGetter gets pods of pod volumes once, nil if pod was not found
+type podGetter struct {
+	clusterClient client.Client
+	// <namespace>/<name> : pod
+	pods map[string]*corev1.Pod
+}
+
+func newPodGetter(clusterClient client.Client) *podGetter {
+	return &podGetter{clusterClient: clusterClient, pods: map[string]*corev1.Pod{}}
+}
+
+func (getter *podGetter) get(namespace string, name string) *corev1.Pod {
+	key := namespace + "/" + name
+	pod, ok := getter.pods[key]
+	if ok {
+		return pod
+	}
+	pod = &corev1.Pod{}
+	err := getter.clusterClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, pod)
+	if err != nil {
+		fmt.Println(err)
+		pod = nil
+	}
+	getter.pods[key] = pod
+	return pod
+}
+
+// podVolumeType returns source type of volume of pod, Velero labels pod volumes of PersistentVolumeClaims with their UID
+func podVolumeType(labels map[string]string, pod *corev1.Pod, volume string) string {
+	if _, ok := labels[velerov1.PVCUIDLabel]; ok {
+		return "persistentVolumeClaim"
+	}
+	if pod == nil {
+		return unknownText
+	}
+	for _, podVolume := range pod.Spec.Volumes {
+		if podVolume.Name != volume {
+			continue
+		}
+		switch {
+		case podVolume.PersistentVolumeClaim != nil:
+			return "persistentVolumeClaim"
+		case podVolume.EmptyDir != nil:
+			return "emptyDir"
+		case podVolume.HostPath != nil:
+			return "hostPath"
+		case podVolume.Ephemeral != nil:
+			return "ephemeral"
+		case podVolume.CSI != nil:
+			return "csi"
+		case podVolume.ConfigMap != nil:
+			return "configMap"
+		case podVolume.Secret != nil:
+			return "secret"
+		case podVolume.Projected != nil:
+			return "projected"
+		case podVolume.DownwardAPI != nil:
+			return "downwardAPI"
+		}
+		return "other"
+	}
+	return unknownText
+}
+
+// podVolumeBackupType returns volume type of PodVolumeBackup, its pod is only got if volume is not a PersistentVolumeClaim
+func podVolumeBackupType(pods *podGetter, podVolumeBackup *velerov1.PodVolumeBackup) string {
+	if _, ok := podVolumeBackup.Labels[velerov1.PVCUIDLabel]; ok {
+		return podVolumeType(podVolumeBackup.Labels, nil, podVolumeBackup.Spec.Volume)
+	}
+	return podVolumeType(podVolumeBackup.Labels, pods.get(podVolumeBackup.Spec.Pod.Namespace, podVolumeBackup.Spec.Pod.Name), podVolumeBackup.Spec.Volume)
+}
+
+func nodeAgentPodOnNode(nodeAgentPodList *corev1.PodList, node string) *corev1.Pod {
+	if nodeAgentPodList == nil {
+		return nil
+	}
+	for _, pod := range nodeAgentPodList.Items {
+		if pod.Spec.NodeName == node {
+			return &pod
+		}
+	}
+	return nil
+}
+
+// nodeAgentLogExcerpt returns link to node-agent pod logs and its last lines that contain match
+func (summary *Summary) nodeAgentLogExcerpt(outputPath string, clientset kubernetes.Interface, logsSince time.Duration, nodeAgentPod *corev1.Pod, match string) (string, string) {
+	key := nodeAgentPod.Namespace + "/" + nodeAgentPod.Name
+	logsFile, ok := summary.nodeAgentLogsFiles[key]
+	if !ok {
+		logs, err := gather.PodLogs(clientset, nodeAgentPod, "", logsSince)
+		if err != nil {
+			fmt.Println(err)
+			summary.nodeAgentLogsFiles[key] = fmt.Sprintf("❌ %s", err)
+		} else {
+			summary.addLogs("node-agent pod "+key, logs)
+			logsPath := fmt.Sprintf("namespaces/%s/velero.io/node-agent/%s.log", nodeAgentPod.Namespace, nodeAgentPod.Name)
+			summary.nodeAgentLogsFiles[key] = summary.createFile(outputPath, logsPath, logs, "logs")
+			summary.nodeAgentLogsPaths[key] = outputPath + logsPath
+		}
+		logsFile = summary.nodeAgentLogsFiles[key]
+	}
+
+	// logs are read from their file, so they are not kept in memory
+	logsPath, ok := summary.nodeAgentLogsPaths[key]
+	if !ok {
+		return logsFile, ""
+	}
+	file, err := os.Open(logsPath)
+	if err != nil {
+		fmt.Println(err)
+		return logsFile, ""
+	}
+	defer file.Close()
+	var excerpt []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.Contains(line, match) {
+			excerpt = append(excerpt, line)
+			if len(excerpt) > nodeAgentLogExcerptLines {
+				excerpt = excerpt[1:]
+			}
+		}
+	}
+	if len(excerpt) > nodeAgentLogExcerptLines {
+		excerpt = excerpt[len(excerpt)-nodeAgentLogExcerptLines:]
+	}
+	return logsFile, strings.Join(excerpt, "\n")
+}
+
+func (summary *Summary) failingPodVolumeText(outputPath string, clientset kubernetes.Interface, logsSince time.Duration, nodeAgentPodList *corev1.PodList, kind string, namespace string, name string, podVolume string, node string, message string) string {
+	nodeAgentText := "❌ no node-agent pod found on node"
+	excerptText := ""
+	if node == unknownText {
+		// node of PodVolumeRestores is the node of the restored pod
+		nodeAgentText = "node of restored pod is unknown, so its node-agent pod can not be found"
+	} else if nodeAgentPod := nodeAgentPodOnNode(nodeAgentPodList, node); nodeAgentPod != nil {
+		logsFile, excerpt := summary.nodeAgentLogExcerpt(outputPath, clientset, logsSince, nodeAgentPod, name)
+		nodeAgentText = fmt.Sprintf("%s %s", nodeAgentPod.Name, logsFile)
+		if len(excerpt) != 0 {
+			excerptText = fmt.Sprintf("\n\n```\n%s\n```\n", excerpt)
+		}
+	}
+	return fmt.Sprintf(
+		"❌ %s **%v** in **%v** namespace for volume **%v** on node **%v** failed: %s\n\nnode-agent pod: %s%s\n\n",
+		kind, name, namespace, podVolume, node, message, nodeAgentText, excerptText,
+	)
+}
+
+func (summary *Summary) ReplacePodVolumeBackupsBreakdownSection(outputPath string, podVolumeBackupList *velerov1.PodVolumeBackupList, nodeAgentPodList *corev1.PodList, clusterClient client.Client, clientset kubernetes.Interface, logsSince time.Duration) {
+	if podVolumeBackupList == nil || len(podVolumeBackupList.Items) == 0 {
+		summary.replaces["POD_VOLUME_BACKUPS_BREAKDOWN"] = "❌ No PodVolumeBackup was found in the cluster"
+		return
+	}
+
+	byNode := map[string]*podVolumeAggregate{}
+	byBackup := map[string]*podVolumeAggregate{}
+	byUploaderType := map[string]*podVolumeAggregate{}
+	byVolumeType := map[string]*podVolumeAggregate{}
+	pods := newPodGetter(clusterClient)
+	failingText := ""
+	for _, podVolumeBackup := range podVolumeBackupList.Items {
+		phase := string(podVolumeBackup.Status.Phase)
+		var start, completion *time.Time
+		if podVolumeBackup.Status.StartTimestamp != nil && podVolumeBackup.Status.CompletionTimestamp != nil {
+			start = &podVolumeBackup.Status.StartTimestamp.Time
+			completion = &podVolumeBackup.Status.CompletionTimestamp.Time
+		}
+		backupName := podVolumeBackup.Namespace + "/" + podVolumeBackup.Labels[velerov1.BackupNameLabel]
+		for _, aggregate := range []*podVolumeAggregate{
+			addToAggregate(byNode, podVolumeBackup.Spec.Node),
+			addToAggregate(byBackup, backupName),
+			addToAggregate(byUploaderType, podVolumeBackup.Spec.UploaderType),
+			addToAggregate(byVolumeType, podVolumeBackupType(pods, &podVolumeBackup)),
+		} {
+			aggregate.add(phase, podVolumeBackup.Status.Progress.BytesDone, start, completion)
+		}
+
+		if podVolumeBackup.Status.Phase == velerov1.PodVolumeBackupPhaseFailed {
+			failingText += summary.failingPodVolumeText(
+				outputPath, clientset, logsSince, nodeAgentPodList,
+				"PodVolumeBackup", podVolumeBackup.Namespace, podVolumeBackup.Name,
+				podVolumeBackup.Spec.Pod.Namespace+"/"+podVolumeBackup.Spec.Pod.Name+":"+podVolumeBackup.Spec.Volume,
+				podVolumeBackup.Spec.Node, podVolumeBackup.Status.Message,
+			)
+		}
+	}
+
+	summary.replaces["POD_VOLUME_BACKUPS_BREAKDOWN"] += podVolumeAggregateTable("Node", byNode)
+	summary.replaces["POD_VOLUME_BACKUPS_BREAKDOWN"] += podVolumeAggregateTable("Backup", byBackup)
+	summary.replaces["POD_VOLUME_BACKUPS_BREAKDOWN"] += podVolumeAggregateTable("spec.uploaderType", byUploaderType)
+	summary.replaces["POD_VOLUME_BACKUPS_BREAKDOWN"] += podVolumeAggregateTable("volume type", byVolumeType)
+	if len(failingText) != 0 {
+		summary.replaces["POD_VOLUME_BACKUPS_BREAKDOWN"] += "Failing volumes\n\n" + failingText
+	}
+}
+
+func (summary *Summary) ReplacePodVolumeRestoresBreakdownSection(outputPath string, podVolumeRestoreList *velerov1.PodVolumeRestoreList, nodeAgentPodList *corev1.PodList, clusterClient client.Client, clientset kubernetes.Interface, logsSince time.Duration) {
+	if podVolumeRestoreList == nil || len(podVolumeRestoreList.Items) == 0 {
+		summary.replaces["POD_VOLUME_RESTORES_BREAKDOWN"] = "❌ No PodVolumeRestore was found in the cluster"
+		return
+	}
+
+	byNode := map[string]*podVolumeAggregate{}
+	byRestore := map[string]*podVolumeAggregate{}
+	byUploaderType := map[string]*podVolumeAggregate{}
+	byVolumeType := map[string]*podVolumeAggregate{}
+	pods := newPodGetter(clusterClient)
+	failingText := ""
+	for _, podVolumeRestore := range podVolumeRestoreList.Items {
+		phase := string(podVolumeRestore.Status.Phase)
+		var start, completion *time.Time
+		if podVolumeRestore.Status.StartTimestamp != nil && podVolumeRestore.Status.CompletionTimestamp != nil {
+			start = &podVolumeRestore.Status.StartTimestamp.Time
+			completion = &podVolumeRestore.Status.CompletionTimestamp.Time
+		}
+		// PodVolumeRestores do not record the node, it is the node of the restored pod
+		node := unknownText
+		restoredPod := pods.get(podVolumeRestore.Spec.Pod.Namespace, podVolumeRestore.Spec.Pod.Name)
+		if restoredPod != nil && len(restoredPod.Spec.NodeName) != 0 {
+			node = restoredPod.Spec.NodeName
+		}
+		restoreName := podVolumeRestore.Namespace + "/" + podVolumeRestore.Labels[velerov1.RestoreNameLabel]
+		for _, aggregate := range []*podVolumeAggregate{
+			addToAggregate(byNode, node),
+			addToAggregate(byRestore, restoreName),
+			addToAggregate(byUploaderType, podVolumeRestore.Spec.UploaderType),
+			addToAggregate(byVolumeType, podVolumeType(podVolumeRestore.Labels, restoredPod, podVolumeRestore.Spec.Volume)),
+		} {
+			aggregate.add(phase, podVolumeRestore.Status.Progress.BytesDone, start, completion)
+		}
+
+		if podVolumeRestore.Status.Phase == velerov1.PodVolumeRestorePhaseFailed {
+			failingText += summary.failingPodVolumeText(
+				outputPath, clientset, logsSince, nodeAgentPodList,
+				"PodVolumeRestore", podVolumeRestore.Namespace, podVolumeRestore.Name,
+				podVolumeRestore.Spec.Pod.Namespace+"/"+podVolumeRestore.Spec.Pod.Name+":"+podVolumeRestore.Spec.Volume,
+				node, podVolumeRestore.Status.Message,
+			)
+		}
+	}
+
+	summary.replaces["POD_VOLUME_RESTORES_BREAKDOWN"] += podVolumeAggregateTable("Node", byNode)
+	summary.replaces["POD_VOLUME_RESTORES_BREAKDOWN"] += podVolumeAggregateTable("Restore", byRestore)
+	summary.replaces["POD_VOLUME_RESTORES_BREAKDOWN"] += podVolumeAggregateTable("spec.uploaderType", byUploaderType)
+	summary.replaces["POD_VOLUME_RESTORES_BREAKDOWN"] += podVolumeAggregateTable("volume type", byVolumeType)
+	if len(failingText) != 0 {
+		summary.replaces["POD_VOLUME_RESTORES_BREAKDOWN"] += "Failing volumes\n\n" + failingText
+	}
+}
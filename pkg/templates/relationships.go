@@ -0,0 +1,57 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+)
+
+// child kind : yaml file written by its section, formatted with child namespace
+var childYAMLPaths = map[string]string{
+	"DataUpload":       "namespaces/%s/velero.io/datauploads/datauploads.yaml",
+	"DataDownload":     "namespaces/%s/velero.io/datadownloads/datadownloads.yaml",
+	"PodVolumeBackup":  "namespaces/%s/velero.io/podvolumebackups/podvolumebackups.yaml",
+	"PodVolumeRestore": "namespaces/%s/velero.io/podvolumerestores/podvolumerestores.yaml",
+	"VolumeSnapshot":   "namespaces/%s/snapshot.storage.k8s.io/volumesnapshots/volumesnapshots.yaml",
+	"DownloadRequest":  "namespaces/%s/velero.io/downloadrequests/downloadrequests.yaml",
+	"BackupRepository": "namespaces/%s/velero.io/backuprepositories/backuprepositories.yaml",
+}
+
+func childLink(child gather.Child) string {
+	return fmt.Sprintf("[%s %s/%s](%s)", child.Kind, child.Namespace, child.Name, fmt.Sprintf(childYAMLPaths[child.Kind], child.Namespace))
+}
+
+// childrenText returns per kind counts and worst child status of a Backup or Restore
+func childrenText(children []gather.Child) (string, string) {
+	worst, ok := gather.WorstChild(children)
+	if !ok {
+		return "-", "-"
+	}
+
+	countByKind := map[string]int{}
+	for _, child := range children {
+		countByKind[child.Kind]++
+	}
+	var counts []string
+	for _, kind := range slices.Sorted(maps.Keys(countByKind)) {
+		counts = append(counts, fmt.Sprintf("%s: %d", kind, countByKind[kind]))
+	}
+
+	phase := worst.Phase
+	if len(phase) == 0 {
+		phase = "no status"
+	}
+	worstText := ""
+	switch worst.Severity() {
+	case gather.ChildSeverityOK:
+		worstText = "✅ all children ok"
+	case gather.ChildSeverityError:
+		worstText = fmt.Sprintf("❌ %s %s", childLink(worst), phase)
+	default:
+		worstText = fmt.Sprintf("⚠️ %s %s", childLink(worst), phase)
+	}
+	return strings.Join(counts, "<br>"), worstText
+}
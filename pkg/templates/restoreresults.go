@@ -0,0 +1,215 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"github.com/vmware-tanzu/velero/pkg/util/results"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/logs"
+)
+
+const (
+	veleroResultsText      = "(velero)"
+	clusterResultsText     = "(cluster)"
+	otherRestoreIssueText  = "other"
+	restoreIssueExampleMax = 200
+)
+
+var (
+	// category and lowercase substrings of messages in it, first match wins
+	restoreIssueCategories = []struct {
+		name       string
+		substrings []string
+	}{
+		{"already exists", []string{"already exists"}},
+		{"SCC/RBAC denied", []string{"forbidden", "security context constraint", "cannot create resource", "permission denied", "is not allowed"}},
+		{"missing CRD", []string{"no matches for kind", "could not find the requested resource", "is not registered", "no kind"}},
+		{"namespace mapping", []string{"namespace mapping", "namespacemapping", "error creating namespace", "namespace is terminating", "namespaces \""}},
+		{"PV provisioning", []string{"persistentvolume", "provision", "storageclass", "volumesnapshot", "pvc"}},
+	}
+)
+
+func categorizeRestoreMessage(message string) string {
+	lowercase := strings.ToLower(message)
+	for _, category := range restoreIssueCategories {
+		for _, substring := range category.substrings {
+			if strings.Contains(lowercase, substring) {
+				return category.name
+			}
+		}
+	}
+	return otherRestoreIssueText
+}
+
+type restoreIssueCount struct {
+	warnings int
+	errors   int
+	example  string
+}
+
+func (count *restoreIssueCount) add(isError bool, message string) {
+	if isError {
+		count.errors++
+	} else {
+		count.warnings++
+	}
+	if len(count.example) == 0 {
+		if len(message) > restoreIssueExampleMax {
+			cut := restoreIssueExampleMax
+			// do not split a multi-byte character
+			for cut > 0 && !utf8.RuneStart(message[cut]) {
+				cut--
+			}
+			message = message[:cut] + "..."
+		}
+		// keep markdown table valid
+		count.example = inlineCode(strings.ReplaceAll(message, "|", "\\|"))
+	}
+}
+
+// inlineCode returns text as markdown inline code, with a delimiter longer than any backticks in text
+func inlineCode(text string) string {
+	if !strings.Contains(text, "`") {
+		return "`" + text + "`"
+	}
+	delimiter := "``"
+	for strings.Contains(text, delimiter) {
+		delimiter += "`"
+	}
+	return delimiter + " " + text + " " + delimiter
+}
+
+// addRestoreLogs categorizes warnings and errors of a gathered Restore log
+func (summary *Summary) addRestoreLogs(key string, text string) {
+	// category : count
+	logCounts := map[string]*restoreIssueCount{}
+	for _, entry := range logs.Parse(text) {
+		if entry.Level != "error" && entry.Level != "warning" {
+			continue
+		}
+		category := categorizeRestoreMessage(entry.Text())
+		if logCounts[category] == nil {
+			logCounts[category] = &restoreIssueCount{}
+		}
+		logCounts[category].add(entry.Level == "error", entry.Text())
+	}
+	summary.restoreLogCounts[key] = logCounts
+}
+
+// restoreResults returns RestoreResults of a Restore and a link to it, saved next to Restore logs
+func (summary *Summary) restoreResults(outputPath string, artifacts *gather.Artifacts, restore *velerov1.Restore) (map[string]results.Result, string, error) {
+	content, err := artifacts.Download(restore.Namespace, restore.Name, velerov1.DownloadTargetKindRestoreResults)
+	if err != nil {
+		return nil, "", err
+	}
+	link := summary.createFile(
+		outputPath,
+		fmt.Sprintf("namespaces/%s/velero.io/restores/results-%s.json", restore.Namespace, restore.Name),
+		string(content),
+		"results",
+	)
+	resultMap := map[string]results.Result{}
+	err = json.Unmarshal(content, &resultMap)
+	return resultMap, link, err
+}
+
+func (summary *Summary) ReplaceRestoreResultsSection(outputPath string, artifacts *gather.Artifacts, restoreList *velerov1.RestoreList) {
+	if restoreList == nil || len(restoreList.Items) == 0 {
+		summary.replaces["RESTORE_RESULTS"] = "❌ No Restore was found in the cluster"
+		return
+	}
+
+	found := false
+	for _, restore := range restoreList.Items {
+		if restore.Status.Warnings == 0 && restore.Status.Errors == 0 {
+			continue
+		}
+		found = true
+		key := restore.Namespace + "/" + restore.Name
+
+		// namespace : category : count
+		resultCounts := map[string]map[string]*restoreIssueCount{}
+		addResult := func(namespace string, isError bool, message string) {
+			if resultCounts[namespace] == nil {
+				resultCounts[namespace] = map[string]*restoreIssueCount{}
+			}
+			category := categorizeRestoreMessage(message)
+			if resultCounts[namespace][category] == nil {
+				resultCounts[namespace][category] = &restoreIssueCount{}
+			}
+			resultCounts[namespace][category].add(isError, message)
+		}
+		resultMap, resultsLink, err := summary.restoreResults(outputPath, artifacts, &restore)
+		if err != nil {
+			fmt.Println(err)
+		}
+		for kind, result := range resultMap {
+			isError := kind == "errors"
+			for _, message := range result.Velero {
+				addResult(veleroResultsText, isError, message)
+			}
+			for _, message := range result.Cluster {
+				addResult(clusterResultsText, isError, message)
+			}
+			for namespace, messages := range result.Namespaces {
+				for _, message := range messages {
+					addResult(namespace, isError, message)
+				}
+			}
+		}
+
+		// category : count
+		logCounts := summary.restoreLogCounts[key]
+
+		summary.replaces["RESTORE_RESULTS"] += fmt.Sprintf(
+			"Restore **%v** in **%v** namespace, **%d** warnings and **%d** errors",
+			restore.Name, restore.Namespace, restore.Status.Warnings, restore.Status.Errors,
+		)
+		if len(resultsLink) != 0 {
+			summary.replaces["RESTORE_RESULTS"] += ", " + resultsLink
+		}
+		summary.replaces["RESTORE_RESULTS"] += "\n\n"
+		if err != nil {
+			summary.replaces["RESTORE_RESULTS"] += fmt.Sprintf("❌ Unable to get restore results: %s\n\n", err)
+		} else {
+			summary.replaces["RESTORE_RESULTS"] += "| Namespace | category | warnings | errors | example |\n| --- | --- | --- | --- | --- |\n"
+			for _, namespace := range slices.Sorted(maps.Keys(resultCounts)) {
+				for _, category := range slices.Sorted(maps.Keys(resultCounts[namespace])) {
+					count := resultCounts[namespace][category]
+					summary.replaces["RESTORE_RESULTS"] += fmt.Sprintf(
+						"| %v | %v | %d | %d | %s |\n",
+						namespace, category, count.warnings, count.errors, count.example,
+					)
+					if count.errors != 0 && category != otherRestoreIssueText {
+						summary.replaces["ERRORS"] += fmt.Sprintf(
+							"❌ Restore **%v** in **%v** namespace has **%d** %s errors in **%v**, for example %s\n\n",
+							restore.Name, restore.Namespace, count.errors, category, namespace, count.example,
+						)
+					}
+				}
+			}
+			summary.replaces["RESTORE_RESULTS"] += "\n"
+		}
+		if len(logCounts) != 0 {
+			summary.replaces["RESTORE_RESULTS"] += "| log category | warning lines | error lines | example |\n| --- | --- | --- | --- |\n"
+			for _, category := range slices.Sorted(maps.Keys(logCounts)) {
+				count := logCounts[category]
+				summary.replaces["RESTORE_RESULTS"] += fmt.Sprintf(
+					"| %v | %d | %d | %s |\n",
+					category, count.warnings, count.errors, count.example,
+				)
+			}
+			summary.replaces["RESTORE_RESULTS"] += "\n"
+		}
+	}
+	if !found {
+		summary.replaces["RESTORE_RESULTS"] = "No Restore with warnings or errors was found in the cluster"
+	}
+}
@@ -0,0 +1,172 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	oadpv1alpha1 "github.com/openshift/oadp-operator/api/v1alpha1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"github.com/vmware-tanzu/velero/pkg/label"
+)
+
+const (
+	// velero list operations and backup sync get slower as backup count grows
+	backupCountWarningThreshold = 800
+	backupCountErrorThreshold   = 1000
+	noScheduleText              = "(no schedule)"
+	// Velero garbage collection default frequency, expired Backups are only deleted when it runs
+	defaultGarbageCollectionFrequency = time.Hour
+)
+
+var backupAgeBuckets = []struct {
+	title  string
+	maxAge time.Duration
+}{
+	{title: "< 1d", maxAge: 24 * time.Hour},
+	{title: "1d - 7d", maxAge: 7 * 24 * time.Hour},
+	{title: "7d - 30d", maxAge: 30 * 24 * time.Hour},
+	{title: "30d - 90d", maxAge: 90 * 24 * time.Hour},
+	{title: "> 90d", maxAge: 0},
+}
+
+type backupRetentionRow struct {
+	ageBuckets []int
+	noTTL      int
+	expired    int
+	total      int
+}
+
+func backupAgeBucket(age time.Duration) int {
+	for index, bucket := range backupAgeBuckets {
+		if bucket.maxAge == 0 || age < bucket.maxAge {
+			return index
+		}
+	}
+	return len(backupAgeBuckets) - 1
+}
+
+// garbageCollectionFrequency returns the longest garbage collection frequency of DataProtectionApplications
+func garbageCollectionFrequency(dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList) time.Duration {
+	frequency := defaultGarbageCollectionFrequency
+	if dataProtectionApplicationList == nil {
+		return frequency
+	}
+	for _, dpa := range dataProtectionApplicationList.Items {
+		if dpa.Spec.Configuration == nil || dpa.Spec.Configuration.Velero == nil || dpa.Spec.Configuration.Velero.Args == nil ||
+			dpa.Spec.Configuration.Velero.Args.GarbageCollectionFrequency == nil {
+			continue
+		}
+		if dpaFrequency := *dpa.Spec.Configuration.Velero.Args.GarbageCollectionFrequency; dpaFrequency > frequency {
+			frequency = dpaFrequency
+		}
+	}
+	return frequency
+}
+
+func (summary *Summary) ReplaceBackupRetentionSection(backupList *velerov1.BackupList, deleteBackupRequestList *velerov1.DeleteBackupRequestList, dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList) {
+	if backupList == nil || len(backupList.Items) == 0 {
+		summary.replaces["BACKUP_RETENTION"] = "❌ No Backup was found in the cluster"
+		return
+	}
+
+	now := time.Now()
+	// expired Backups are only flagged after garbage collection had time to run
+	gracePeriod := garbageCollectionFrequency(dataProtectionApplicationList)
+	// namespace : schedule : row
+	rowsByNamespace := map[string]map[string]*backupRetentionRow{}
+	for _, backup := range backupList.Items {
+		schedule := backup.Labels[velerov1.ScheduleNameLabel]
+		if len(schedule) == 0 {
+			schedule = noScheduleText
+		}
+		if rowsByNamespace[backup.Namespace] == nil {
+			rowsByNamespace[backup.Namespace] = map[string]*backupRetentionRow{}
+		}
+		row, ok := rowsByNamespace[backup.Namespace][schedule]
+		if !ok {
+			row = &backupRetentionRow{ageBuckets: make([]int, len(backupAgeBuckets))}
+			rowsByNamespace[backup.Namespace][schedule] = row
+		}
+		row.total++
+		row.ageBuckets[backupAgeBucket(now.Sub(backup.CreationTimestamp.Time))]++
+
+		if backup.Spec.TTL.Duration == 0 {
+			row.noTTL++
+		}
+
+		if backup.Status.Expiration != nil && backup.Status.Expiration.Time.Add(gracePeriod).Before(now) {
+			row.expired++
+			deleteBackupRequestsText := "no DeleteBackupRequest was found for it"
+			if deleteBackupRequestList != nil {
+				var phases []string
+				for _, deleteBackupRequest := range deleteBackupRequestList.Items {
+					if deleteBackupRequest.Namespace == backup.Namespace &&
+						deleteBackupRequest.Labels[velerov1.BackupNameLabel] == label.GetValidName(backup.Name) {
+						phase := string(deleteBackupRequest.Status.Phase)
+						if len(phase) == 0 {
+							phase = "no status"
+						}
+						phases = append(phases, fmt.Sprintf("%s (%s)", deleteBackupRequest.Name, phase))
+					}
+				}
+				if len(phases) != 0 {
+					deleteBackupRequestsText = fmt.Sprintf("related DeleteBackupRequests: %v", phases)
+				}
+			}
+			summary.replaces["ERRORS"] += fmt.Sprintf(
+				"❌ Backup **%v** in **%v** namespace **expired at %s** but still exists, garbage collection (every %s) may be stuck (%s)\n\n",
+				backup.Name, backup.Namespace, backup.Status.Expiration.UTC().Format(time.RFC3339), gracePeriod, deleteBackupRequestsText,
+			)
+		}
+	}
+
+	summary.replaces["BACKUP_RETENTION"] += "| Namespace | Schedule | total |"
+	separator := "| --- | --- | --- |"
+	for _, bucket := range backupAgeBuckets {
+		summary.replaces["BACKUP_RETENTION"] += " age " + bucket.title + " |"
+		separator += " --- |"
+	}
+	summary.replaces["BACKUP_RETENTION"] += " no spec.ttl | expired |\n" + separator + " --- | --- |\n"
+
+	for _, namespace := range slices.Sorted(maps.Keys(rowsByNamespace)) {
+		namespaceTotal := 0
+		for _, schedule := range slices.Sorted(maps.Keys(rowsByNamespace[namespace])) {
+			row := rowsByNamespace[namespace][schedule]
+			namespaceTotal += row.total
+
+			noTTLText := "0"
+			if row.noTTL != 0 {
+				noTTLText = fmt.Sprintf("⚠️ %d", row.noTTL)
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ **%d** Backups of schedule **%v** with **no spec.ttl** in **%v** namespace\n\n",
+					row.noTTL, schedule, namespace,
+				)
+			}
+			expiredText := "0"
+			if row.expired != 0 {
+				expiredText = fmt.Sprintf("❌ %d", row.expired)
+			}
+			summary.replaces["BACKUP_RETENTION"] += fmt.Sprintf("| %v | %v | %d |", namespace, schedule, row.total)
+			for _, count := range row.ageBuckets {
+				summary.replaces["BACKUP_RETENTION"] += fmt.Sprintf(" %d |", count)
+			}
+			summary.replaces["BACKUP_RETENTION"] += fmt.Sprintf(" %s | %s |\n", noTTLText, expiredText)
+		}
+
+		if namespaceTotal >= backupCountErrorThreshold {
+			summary.replaces["ERRORS"] += fmt.Sprintf(
+				"❌ **%d** Backups in **%v** namespace, Velero performance degrades above **%d** Backups\n\n",
+				namespaceTotal, namespace, backupCountErrorThreshold,
+			)
+		} else if namespaceTotal >= backupCountWarningThreshold {
+			summary.replaces["ERRORS"] += fmt.Sprintf(
+				"⚠️ **%d** Backups in **%v** namespace, approaching **%d** Backups where Velero performance degrades\n\n",
+				namespaceTotal, namespace, backupCountErrorThreshold,
+			)
+		}
+	}
+
+	summary.replaces["BACKUP_RETENTION"] += fmt.Sprintf("\nTotal of **%d** Backups in the cluster\n", len(backupList.Items))
+}
@@ -0,0 +1,220 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	velerov2alpha1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v2alpha1"
+	"github.com/vmware-tanzu/velero/pkg/label"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+)
+
+const (
+	largestVolumesCount = 10
+	// each download creates a DownloadRequest and may wait for its timeout, so only latest Backups of each Schedule are downloaded
+	volumeInfosBackupsPerSchedule = 3
+)
+
+// backupVolumeInfo is the subset of Velero BackupVolumeInfo (internal package) with data moved to object storage
+type backupVolumeInfo struct {
+	PVCName                  string `json:"pvcName,omitempty"`
+	PVCNamespace             string `json:"pvcNamespace,omitempty"`
+	SnapshotDataMovementInfo *struct {
+		Size int64 `json:"size"`
+	} `json:"snapshotDataMovementInfo,omitempty"`
+	PVBInfo *struct {
+		Size int64 `json:"size,omitempty"`
+	} `json:"pvbInfo,omitempty"`
+}
+
+type storedVolume struct {
+	backup    string
+	namespace string
+	volume    string
+	source    string
+	bytes     int64
+}
+
+func bytesTable(dimension string, totals map[string]int64) string {
+	table := fmt.Sprintf("| %s | bytes |\n| --- | --- |\n", dimension)
+	for _, key := range slices.Sorted(maps.Keys(totals)) {
+		table += fmt.Sprintf("| %v | %s |\n", key, formatBytes(totals[key]))
+	}
+	return table + "\n"
+}
+
+func backupVolumeInfos(artifacts *gather.Artifacts, backup *velerov1.Backup) ([]backupVolumeInfo, error) {
+	content, err := artifacts.Download(backup.Namespace, backup.Name, velerov1.DownloadTargetKindBackupVolumeInfos)
+	if err != nil {
+		return nil, err
+	}
+	var volumeInfos []backupVolumeInfo
+	err = json.Unmarshal(content, &volumeInfos)
+	return volumeInfos, err
+}
+
+func (summary *Summary) ReplaceStorageConsumptionSection(
+	artifacts *gather.Artifacts,
+	backupList *velerov1.BackupList,
+	podVolumeBackupList *velerov1.PodVolumeBackupList,
+	dataUploadList *velerov2alpha1.DataUploadList,
+) {
+	if backupList == nil || len(backupList.Items) == 0 {
+		summary.replaces["STORAGE_CONSUMPTION"] = "❌ No Backup was found in the cluster"
+		return
+	}
+
+	// <namespace>/<backup name label> : volumes
+	volumesByBackup := map[string][]storedVolume{}
+	if podVolumeBackupList != nil {
+		for _, podVolumeBackup := range podVolumeBackupList.Items {
+			backup := podVolumeBackup.Namespace + "/" + podVolumeBackup.Labels[velerov1.BackupNameLabel]
+			volumesByBackup[backup] = append(volumesByBackup[backup], storedVolume{
+				namespace: podVolumeBackup.Spec.Pod.Namespace,
+				volume:    podVolumeBackup.Spec.Pod.Namespace + "/" + podVolumeBackup.Spec.Pod.Name + ":" + podVolumeBackup.Spec.Volume,
+				source:    "PodVolumeBackup",
+				bytes:     podVolumeBackup.Status.Progress.BytesDone,
+			})
+		}
+	}
+	if dataUploadList != nil {
+		for _, dataUpload := range dataUploadList.Items {
+			backup := dataUpload.Namespace + "/" + dataUpload.Labels[velerov1.BackupNameLabel]
+			volumesByBackup[backup] = append(volumesByBackup[backup], storedVolume{
+				namespace: dataUpload.Spec.SourceNamespace,
+				volume:    dataUpload.Spec.SourceNamespace + "/" + dataUpload.Spec.SourcePVC,
+				source:    "DataUpload",
+				bytes:     dataUpload.Status.Progress.BytesDone,
+			})
+		}
+	}
+
+	// Backups synced from object storage do not have PodVolumeBackups and DataUploads in the cluster, their BackupVolumeInfos are downloaded
+	// <namespace>/<schedule> : Backups
+	syncedBackupsBySchedule := map[string][]velerov1.Backup{}
+	for _, backup := range backupList.Items {
+		if len(volumesByBackup[backup.Namespace+"/"+label.GetValidName(backup.Name)]) != 0 ||
+			(backup.Status.Phase != velerov1.BackupPhaseCompleted && backup.Status.Phase != velerov1.BackupPhasePartiallyFailed) {
+			continue
+		}
+		schedule := backup.Labels[velerov1.ScheduleNameLabel]
+		if len(schedule) == 0 {
+			schedule = noScheduleText
+		}
+		syncedBackupsBySchedule[backup.Namespace+"/"+schedule] = append(syncedBackupsBySchedule[backup.Namespace+"/"+schedule], backup)
+	}
+	// <namespace>/<backup name> of Backups to download BackupVolumeInfos
+	downloadBackups := map[string]bool{}
+	notDownloaded := 0
+	for _, backups := range syncedBackupsBySchedule {
+		slices.SortFunc(backups, func(a velerov1.Backup, b velerov1.Backup) int {
+			return b.CreationTimestamp.Compare(a.CreationTimestamp.Time)
+		})
+		for index, backup := range backups {
+			if index < volumeInfosBackupsPerSchedule {
+				downloadBackups[backup.Namespace+"/"+backup.Name] = true
+			} else {
+				notDownloaded++
+			}
+		}
+	}
+
+	byBackup := map[string]int64{}
+	byNamespace := map[string]int64{}
+	byStorageLocation := map[string]int64{}
+	byDay := map[string]int64{}
+	var volumes []storedVolume
+	for _, backup := range backupList.Items {
+		key := backup.Namespace + "/" + backup.Name
+		var backupVolumes []storedVolume
+		for _, volume := range volumesByBackup[backup.Namespace+"/"+label.GetValidName(backup.Name)] {
+			volume.backup = key
+			backupVolumes = append(backupVolumes, volume)
+		}
+		if downloadBackups[key] {
+			volumeInfos, err := backupVolumeInfos(artifacts, &backup)
+			if err != nil {
+				fmt.Println(err)
+			}
+			for _, volumeInfo := range volumeInfos {
+				var size int64
+				if volumeInfo.PVBInfo != nil {
+					size = volumeInfo.PVBInfo.Size
+				} else if volumeInfo.SnapshotDataMovementInfo != nil {
+					size = volumeInfo.SnapshotDataMovementInfo.Size
+				} else {
+					// native and CSI snapshots are not stored in object storage
+					continue
+				}
+				backupVolumes = append(backupVolumes, storedVolume{
+					backup:    key,
+					namespace: volumeInfo.PVCNamespace,
+					volume:    volumeInfo.PVCNamespace + "/" + volumeInfo.PVCName,
+					source:    "BackupVolumeInfos",
+					bytes:     size,
+				})
+			}
+		}
+
+		day := backup.CreationTimestamp.Format(time.DateOnly)
+		if backup.Status.StartTimestamp != nil {
+			day = backup.Status.StartTimestamp.Format(time.DateOnly)
+		}
+		byBackup[key] = 0
+		for _, volume := range backupVolumes {
+			byBackup[key] += volume.bytes
+			byNamespace[volume.namespace] += volume.bytes
+			byStorageLocation[backup.Namespace+"/"+backup.Spec.StorageLocation] += volume.bytes
+			byDay[day] += volume.bytes
+		}
+		volumes = append(volumes, backupVolumes...)
+	}
+
+	var total int64
+	for _, bytesDone := range byBackup {
+		total += bytesDone
+	}
+	summary.replaces["STORAGE_CONSUMPTION"] += fmt.Sprintf(
+		"Total of **%s** moved to object storage by File System Backup and Data Mover, native and CSI snapshots are not included\n\n",
+		formatBytes(total),
+	)
+	if notDownloaded != 0 {
+		summary.replaces["STORAGE_CONSUMPTION"] += fmt.Sprintf(
+			"%d older Backups without PodVolumeBackups and DataUploads in the cluster are counted as zero bytes, "+
+				"BackupVolumeInfos are only downloaded for latest %d Backups of each Schedule\n\n",
+			notDownloaded, volumeInfosBackupsPerSchedule,
+		)
+	}
+	summary.replaces["STORAGE_CONSUMPTION"] += bytesTable("Backup", byBackup)
+	summary.replaces["STORAGE_CONSUMPTION"] += bytesTable("Namespace", byNamespace)
+	summary.replaces["STORAGE_CONSUMPTION"] += bytesTable("BackupStorageLocation", byStorageLocation)
+	summary.replaces["STORAGE_CONSUMPTION"] += bytesTable("Day", byDay)
+
+	if len(volumes) == 0 {
+		return
+	}
+	slices.SortStableFunc(volumes, func(a storedVolume, b storedVolume) int {
+		switch {
+		case a.bytes > b.bytes:
+			return -1
+		case a.bytes < b.bytes:
+			return 1
+		}
+		return 0
+	})
+	if len(volumes) > largestVolumesCount {
+		volumes = volumes[:largestVolumesCount]
+	}
+	summary.replaces["STORAGE_CONSUMPTION"] += "Largest volumes\n\n| Volume | Backup | source | bytes |\n| --- | --- | --- | --- |\n"
+	for _, volume := range volumes {
+		summary.replaces["STORAGE_CONSUMPTION"] += fmt.Sprintf(
+			"| %v | %v | %v | %s |\n",
+			volume.volume, volume.backup, volume.source, formatBytes(volume.bytes),
+		)
+	}
+}
@@ -19,48 +19,105 @@ import (
 	"github.com/vmware-tanzu/velero/pkg/cmd/util/downloadrequest"
 	"github.com/vmware-tanzu/velero/pkg/cmd/util/output"
 	"github.com/vmware-tanzu/velero/pkg/label"
+	"github.com/vmware-tanzu/velero/pkg/repository"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
 	"github.com/mateusoliveira43/oadp-must-gather/pkg/gvk"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/knownissues"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/logs"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/manifest"
+)
+
+const (
+	// maintenance is overdue when last maintenance is older than this times spec.maintenanceFrequency
+	maintenanceOverdueFactor                      = 2
+	maintenanceFailuresThreshold                  = 2
+	backupRepositoriesPerStorageLocationThreshold = 20
 )
 
 var (
 	summaryTemplateReplacesKeys = []string{
 		"MUST_GATHER_VERSION",
 		"ERRORS",
+		"TOP_LOG_ERRORS",
 		"CLUSTER_ID", "OCP_VERSION", "CLOUD", "ARCH", "CLUSTER_VERSION",
 		"OADP_VERSIONS",
+		"SUPPORT_MATRIX",
+		"OLM",
+		"INSTALLATION_CONFLICTS",
 		"DATA_PROTECTION_APPLICATIONS",
+		"NODE_AGENT_COVERAGE",
+		"VIRTUALIZATION",
+		"ACM",
 		"CLOUD_STORAGES",
 		"BACKUP_STORAGE_LOCATIONS",
 		"VOLUME_SNAPSHOT_LOCATIONS",
 		"BACKUPS",
+		"BACKUP_RETENTION",
+		"STORAGE_CONSUMPTION",
 		"RESTORES",
+		"RESTORE_RESULTS",
+		"API_AVAILABILITY",
 		"SCHEDULES",
+		"PERFORMANCE",
 		"BACKUPS_REPOSITORIES",
 		"DATA_UPLOADS",
 		"DATA_DOWNLOADS",
 		"POD_VOLUME_BACKUPS",
+		"POD_VOLUME_BACKUPS_BREAKDOWN",
 		"POD_VOLUME_RESTORES",
+		"POD_VOLUME_RESTORES_BREAKDOWN",
+		"VOLUME_SNAPSHOTS",
 		"DOWNLOAD_REQUESTS",
 		"DELETE_BACKUP_REQUESTS",
 		"SERVER_STATUS_REQUESTS",
 		// TODO NAC after NAC is finished
+		"CSI_SNAPSHOT_MATRIX",
 		"STORAGE_CLASSES",
 		"VOLUME_SNAPSHOT_CLASSES",
 		"CSI_DRIVERS", "OADP_OCP_VERSION",
 		"CUSTOM_RESOURCE_DEFINITION",
+		"SIZE_BUDGET",
 	}
-	summaryTemplateReplaces = map[string]string{}
 )
 
+// Summary is the summary of a gathered cluster and what its sections gather, so clusters can be gathered concurrently
+type Summary struct {
+	// key : value of summary template
+	replaces map[string]string
+	// errors of gathered Backup, Restore and node-agent logs, grouped as each log is gathered, so logs are not kept in memory
+	logErrors *logs.Clusters
+	// known issues found in gathered logs
+	knownIssues *knownissues.Matcher
+	// <namespace>/<name> : category : count of Restore logs warnings and errors
+	restoreLogCounts map[string]map[string]*restoreIssueCount
+	// node-agent pod namespace/name : link to its logs file, to only fetch logs once
+	nodeAgentLogsFiles map[string]string
+	// node-agent pod namespace/name : path of its logs file
+	nodeAgentLogsPaths map[string]string
+	// bytes written by must-gather
+	writtenSize int64
+	// what was truncated or skipped to stay within size budget
+	budgetNotes []string
+	// logs files to fit in size budget
+	logsFiles []logsFile
+}
+
+type logsFile struct {
+	outputPath string
+	path       string
+}
+
 // TODO https://stackoverflow.com/a/31742265
 // TODO https://github.com/kubernetes-sigs/kubebuilder/blob/master/pkg/plugins/golang/v4/scaffolds/internal/templates/readme.go
 // https://deploy-preview-4185--kubebuilder.netlify.app/plugins/extending/extending_cli_features_and_plugins#example-bollerplate
@@ -71,6 +128,10 @@ const summaryTemplate = `# OADP must-gather summary version <<MUST_GATHER_VERSIO
 
 <<ERRORS>>
 
+## Top log errors
+
+<<TOP_LOG_ERRORS>>
+
 ## Cluster information
 
 | Cluster ID | OpenShift version | Cloud provider | Architecture |
@@ -83,12 +144,36 @@ const summaryTemplate = `# OADP must-gather summary version <<MUST_GATHER_VERSIO
 
 <<OADP_VERSIONS>>
 
+### Support matrix
+
+<<SUPPORT_MATRIX>>
+
+### Operator Lifecycle Manager (OLM)
+
+<<OLM>>
+
+### Installation conflicts
+
+<<INSTALLATION_CONFLICTS>>
+
 TODO info about where to find pod logs, events, secrets, configmaps, etc
 
 ### DataProtectionApplications (DPAs)
 
 <<DATA_PROTECTION_APPLICATIONS>>
 
+#### Node-agent coverage
+
+<<NODE_AGENT_COVERAGE>>
+
+### OpenShift Virtualization
+
+<<VIRTUALIZATION>>
+
+### Advanced Cluster Management for Kubernetes (ACM) hub backup
+
+<<ACM>>
+
 ### CloudStorages
 
 <<CLOUD_STORAGES>>
@@ -105,14 +190,34 @@ TODO info about where to find pod logs, events, secrets, configmaps, etc
 
 <<BACKUPS>>
 
+#### Backup retention
+
+<<BACKUP_RETENTION>>
+
+#### Storage consumption
+
+<<STORAGE_CONSUMPTION>>
+
 ### Restores
 
 <<RESTORES>>
 
+#### Restore results
+
+<<RESTORE_RESULTS>>
+
+#### Restore API availability
+
+<<API_AVAILABILITY>>
+
 ### Schedules
 
 <<SCHEDULES>>
 
+#### Duration and throughput trends
+
+<<PERFORMANCE>>
+
 ### BackupRepositories
 
 <<BACKUPS_REPOSITORIES>>
@@ -129,10 +234,22 @@ TODO info about where to find pod logs, events, secrets, configmaps, etc
 
 <<POD_VOLUME_BACKUPS>>
 
+#### PodVolumeBackups breakdown
+
+<<POD_VOLUME_BACKUPS_BREAKDOWN>>
+
 ### PodVolumeRestores
 
 <<POD_VOLUME_RESTORES>>
 
+#### PodVolumeRestores breakdown
+
+<<POD_VOLUME_RESTORES_BREAKDOWN>>
+
+### VolumeSnapshots
+
+<<VOLUME_SNAPSHOTS>>
+
 ### DownloadRequests
 
 <<DOWNLOAD_REQUESTS>>
@@ -149,6 +266,10 @@ TODO info about where to find pod logs, events, secrets, configmaps, etc
 
 TODO once NAC is ready to be used
 
+## CSI snapshot readiness
+
+<<CSI_SNAPSHOT_MATRIX>>
+
 ## Available StorageClasses in cluster
 
 <<STORAGE_CLASSES>>
@@ -166,38 +287,52 @@ TODO once NAC is ready to be used
 ## CustomResourceDefinitions
 
 <<CUSTOM_RESOURCE_DEFINITION>>
+
+## Size budget
+
+<<SIZE_BUDGET>>
 `
 
-func init() {
+// NewSummary returns an empty summary of a cluster, that matches gathered logs against catalog of known issues
+func NewSummary(catalog *knownissues.Catalog) *Summary {
+	summary := &Summary{
+		replaces:           map[string]string{},
+		logErrors:          logs.NewClusters(),
+		knownIssues:        knownissues.NewMatcher(catalog),
+		restoreLogCounts:   map[string]map[string]*restoreIssueCount{},
+		nodeAgentLogsFiles: map[string]string{},
+		nodeAgentLogsPaths: map[string]string{},
+	}
 	for _, key := range summaryTemplateReplacesKeys {
-		summaryTemplateReplaces[key] = ""
+		summary.replaces[key] = ""
 	}
+	return summary
 }
 
-func ReplaceMustGatherVersion(version string) {
-	summaryTemplateReplaces["MUST_GATHER_VERSION"] = "`" + version + "`"
+func (summary *Summary) ReplaceMustGatherVersion(version string) {
+	summary.replaces["MUST_GATHER_VERSION"] = "`" + version + "`"
 }
 
-func ReplaceClusterInformationSection(outputPath string, clusterID string, clusterVersion *openshiftconfigv1.ClusterVersion, infrastructure *openshiftconfigv1.Infrastructure, nodeList *corev1.NodeList) {
-	summaryTemplateReplaces["CLUSTER_ID"] = clusterID
+func (summary *Summary) ReplaceClusterInformationSection(outputPath string, clusterID string, clusterVersion *openshiftconfigv1.ClusterVersion, infrastructure *openshiftconfigv1.Infrastructure, nodeList *corev1.NodeList) {
+	summary.replaces["CLUSTER_ID"] = clusterID
 
 	if clusterVersion != nil {
 		// nil check
-		summaryTemplateReplaces["OCP_VERSION"] = clusterVersion.Status.Desired.Version
-		summaryTemplateReplaces["CLUSTER_VERSION"] = createYAML(outputPath, "cluster-scoped-resources/config.openshift.io/clusterversions.yaml", clusterVersion)
+		summary.replaces["OCP_VERSION"] = clusterVersion.Status.Desired.Version
+		summary.replaces["CLUSTER_VERSION"] = summary.createYAML(outputPath, "cluster-scoped-resources/config.openshift.io/clusterversions.yaml", clusterVersion)
 	} else {
 		// this is code is unreachable?
-		summaryTemplateReplaces["OCP_VERSION"] = "❌ error"
-		summaryTemplateReplaces["OCP_CAPABILITIES"] = "❌ error"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No ClusterVersion found in cluster\n\n"
+		summary.replaces["OCP_VERSION"] = "❌ error"
+		summary.replaces["OCP_CAPABILITIES"] = "❌ error"
+		summary.replaces["ERRORS"] += "⚠️ No ClusterVersion found in cluster\n\n"
 	}
 
 	if infrastructure != nil {
 		cloudProvider := string(infrastructure.Spec.PlatformSpec.Type)
-		summaryTemplateReplaces["CLOUD"] = cloudProvider
+		summary.replaces["CLOUD"] = cloudProvider
 	} else {
-		summaryTemplateReplaces["CLOUD"] = "❌ error"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No Infrastructure found in cluster\n\n"
+		summary.replaces["CLOUD"] = "❌ error"
+		summary.replaces["ERRORS"] += "⚠️ No Infrastructure found in cluster\n\n"
 	}
 
 	if nodeList != nil && len(nodeList.Items) != 0 {
@@ -212,15 +347,15 @@ func ReplaceClusterInformationSection(outputPath string, clusterID string, clust
 				}
 			}
 		}
-		summaryTemplateReplaces["ARCH"] = architectureText
+		summary.replaces["ARCH"] = architectureText
 	} else {
-		summaryTemplateReplaces["ARCH"] = "❌ error"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No Node found in cluster\n\n"
+		summary.replaces["ARCH"] = "❌ error"
+		summary.replaces["ERRORS"] += "⚠️ No Node found in cluster\n\n"
 	}
 	// TODO maybe nil case can be simplified by initializing everything with an error state/message
 }
 
-func ReplaceOADPOperatorInstallationSection(
+func (summary *Summary) ReplaceOADPOperatorInstallationSection(
 	outputPath string,
 	importantCSVsByNamespace map[string][]operatorsv1alpha1.ClusterServiceVersion,
 	foundOADP bool,
@@ -228,8 +363,8 @@ func ReplaceOADPOperatorInstallationSection(
 	oadpOperatorsText string,
 ) {
 	if len(importantCSVsByNamespace) == 0 {
-		summaryTemplateReplaces["OADP_VERSIONS"] = "❌ No OADP Operator was found installed in the cluster\n\nNo related product was found installed in the cluster"
-		summaryTemplateReplaces["ERRORS"] += "🚫 No OADP Operator was found installed in the cluster\n\n"
+		summary.replaces["OADP_VERSIONS"] = "❌ No OADP Operator was found installed in the cluster\n\nNo related product was found installed in the cluster"
+		summary.replaces["ERRORS"] += "🚫 No OADP Operator was found installed in the cluster\n\n"
 	} else {
 		for namespace, csvs := range importantCSVsByNamespace {
 			list := &corev1.List{}
@@ -239,20 +374,20 @@ func ReplaceOADPOperatorInstallationSection(
 				list.Items = append(list.Items, runtime.RawExtension{Object: &csv})
 			}
 			folder := fmt.Sprintf("namespaces/%s/operators.coreos.com/clusterserviceversions", namespace)
-			oadpOperatorsText += createYAML(outputPath, folder+"/clusterserviceversions.yaml", list)
+			oadpOperatorsText += summary.createYAML(outputPath, folder+"/clusterserviceversions.yaml", list)
 		}
 		if !foundOADP {
-			summaryTemplateReplaces["OADP_VERSIONS"] += "❌ No OADP Operator was found installed in the cluster\n\n"
-			summaryTemplateReplaces["ERRORS"] += "🚫 No OADP Operator was found installed in the cluster\n\n"
+			summary.replaces["OADP_VERSIONS"] += "❌ No OADP Operator was found installed in the cluster\n\n"
+			summary.replaces["ERRORS"] += "🚫 No OADP Operator was found installed in the cluster\n\n"
 		}
-		summaryTemplateReplaces["OADP_VERSIONS"] += oadpOperatorsText
+		summary.replaces["OADP_VERSIONS"] += oadpOperatorsText
 		if !foundRelatedProducts {
-			summaryTemplateReplaces["OADP_VERSIONS"] += "No related product was found installed in the cluster"
+			summary.replaces["OADP_VERSIONS"] += "No related product was found installed in the cluster"
 		}
 	}
 }
 
-func ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList) {
+func (summary *Summary) ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList) {
 	if dataProtectionApplicationList != nil && len(dataProtectionApplicationList.Items) != 0 {
 		dataProtectionApplicationsByNamespace := map[string][]oadpv1alpha1.DataProtectionApplication{}
 
@@ -260,7 +395,7 @@ func ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionA
 			dataProtectionApplicationsByNamespace[dataProtectionApplication.Namespace] = append(dataProtectionApplicationsByNamespace[dataProtectionApplication.Namespace], dataProtectionApplication)
 		}
 
-		summaryTemplateReplaces["DATA_PROTECTION_APPLICATIONS"] += "| Namespace | Name | spec.unsupportedOverrides | status.conditions[0] | yaml |\n| --- | --- | --- | --- | --- |\n"
+		summary.replaces["DATA_PROTECTION_APPLICATIONS"] += "| Namespace | Name | spec.unsupportedOverrides | status.conditions[0] | yaml |\n| --- | --- | --- | --- | --- |\n"
 		for namespace, dataProtectionApplications := range dataProtectionApplicationsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -273,7 +408,7 @@ func ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionA
 
 				unsupportedOverridesText := "false"
 				if dataProtectionApplication.Spec.UnsupportedOverrides != nil {
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ DataProtectionApplication **%v** in **%v** namespace is using **unsupportedOverrides**\n\n",
 						dataProtectionApplication.Name, namespace,
 					)
@@ -283,7 +418,7 @@ func ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionA
 				dpaStatus := ""
 				if len(dataProtectionApplication.Status.Conditions) == 0 {
 					dpaStatus = "⚠️ no status"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ DataProtectionApplication **%v** with **no status** in **%v** namespace\n\n",
 						dataProtectionApplication.Name, namespace,
 					)
@@ -293,7 +428,7 @@ func ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionA
 						dpaStatus = fmt.Sprintf("✅ status %s: %s", condition.Type, condition.Status)
 					} else {
 						dpaStatus = fmt.Sprintf("❌ status %s: %s", condition.Type, condition.Status)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ DataProtectionApplication **%v** with **status %s: %s** in **%v** namespace\n\n",
 							dataProtectionApplication.Name, condition.Type, condition.Status, namespace,
 						)
@@ -301,21 +436,21 @@ func ReplaceDataProtectionApplicationsSection(outputPath string, dataProtectionA
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["DATA_PROTECTION_APPLICATIONS"] += fmt.Sprintf(
+				summary.replaces["DATA_PROTECTION_APPLICATIONS"] += fmt.Sprintf(
 					"| %v | %v | %v | %v | %s |\n",
 					namespace, dataProtectionApplication.Name, unsupportedOverridesText, dpaStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["DATA_PROTECTION_APPLICATIONS"] = "❌ No DataProtectionApplication was found in the cluster"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No DataProtectionApplication was found in the cluster\n\n"
+		summary.replaces["DATA_PROTECTION_APPLICATIONS"] = "❌ No DataProtectionApplication was found in the cluster"
+		summary.replaces["ERRORS"] += "⚠️ No DataProtectionApplication was found in the cluster\n\n"
 	}
 }
 
-func ReplaceCloudStoragesSection(outputPath string, cloudStorageList *oadpv1alpha1.CloudStorageList) {
+func (summary *Summary) ReplaceCloudStoragesSection(outputPath string, cloudStorageList *oadpv1alpha1.CloudStorageList) {
 	if cloudStorageList != nil && len(cloudStorageList.Items) != 0 {
 		cloudStorageByNamespace := map[string][]oadpv1alpha1.CloudStorage{}
 
@@ -323,7 +458,7 @@ func ReplaceCloudStoragesSection(outputPath string, cloudStorageList *oadpv1alph
 			cloudStorageByNamespace[cloudStorage.Namespace] = append(cloudStorageByNamespace[cloudStorage.Namespace], cloudStorage)
 		}
 
-		summaryTemplateReplaces["CLOUD_STORAGES"] += "| Namespace | Name | yaml |\n| --- | --- | --- |\n"
+		summary.replaces["CLOUD_STORAGES"] += "| Namespace | Name | yaml |\n| --- | --- | --- |\n"
 		for namespace, cloudStorages := range cloudStorageByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -335,20 +470,20 @@ func ReplaceCloudStoragesSection(outputPath string, cloudStorageList *oadpv1alph
 				list.Items = append(list.Items, runtime.RawExtension{Object: &cloudStorage})
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["BACKUPS"] += fmt.Sprintf(
+				summary.replaces["BACKUPS"] += fmt.Sprintf(
 					"| %v | %v | %s |\n",
 					namespace, cloudStorage.Name, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["CLOUD_STORAGES"] = "❌ No CloudStorage was found in the cluster"
+		summary.replaces["CLOUD_STORAGES"] = "❌ No CloudStorage was found in the cluster"
 	}
 }
 
-func ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocationList *velerov1.BackupStorageLocationList) {
+func (summary *Summary) ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocationList *velerov1.BackupStorageLocationList) {
 	if backupStorageLocationList != nil && len(backupStorageLocationList.Items) != 0 {
 		backupStorageLocationsByNamespace := map[string][]velerov1.BackupStorageLocation{}
 
@@ -356,7 +491,7 @@ func ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocati
 			backupStorageLocationsByNamespace[backupStorageLocation.Namespace] = append(backupStorageLocationsByNamespace[backupStorageLocation.Namespace], backupStorageLocation)
 		}
 
-		summaryTemplateReplaces["BACKUP_STORAGE_LOCATIONS"] += "| Namespace | Name | spec.default | status.phase | yaml |\n| --- | --- | --- | --- | --- |\n"
+		summary.replaces["BACKUP_STORAGE_LOCATIONS"] += "| Namespace | Name | spec.default | status.phase | yaml |\n| --- | --- | --- | --- | --- |\n"
 		for namespace, backupStorageLocations := range backupStorageLocationsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -371,7 +506,7 @@ func ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocati
 				bslStatusPhase := backupStorageLocation.Status.Phase
 				if len(bslStatusPhase) == 0 {
 					bslStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ BackupStorageLocation **%v** with **no status phase** in **%v** namespace\n\n",
 						backupStorageLocation.Name, namespace,
 					)
@@ -380,7 +515,7 @@ func ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocati
 						bslStatus = fmt.Sprintf("✅ status phase %s", bslStatusPhase)
 					} else {
 						bslStatus = fmt.Sprintf("❌ status phase %s", bslStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ BackupStorageLocation **%v** with **status phase %s** in **%v** namespace\n\n",
 							backupStorageLocation.Name, bslStatusPhase, namespace,
 						)
@@ -388,7 +523,7 @@ func ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocati
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["BACKUP_STORAGE_LOCATIONS"] += fmt.Sprintf(
+				summary.replaces["BACKUP_STORAGE_LOCATIONS"] += fmt.Sprintf(
 					"| %v | %v | %t | %v | %s |\n",
 					namespace, backupStorageLocation.Name, backupStorageLocation.Spec.Default, bslStatus, link,
 				)
@@ -401,15 +536,15 @@ func ReplaceBackupStorageLocationsSection(outputPath string, backupStorageLocati
 				// velero-sample-1   Unavailable   22s              112s   true
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["BACKUP_STORAGE_LOCATIONS"] = "❌ No BackupStorageLocation was found in the cluster"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No BackupStorageLocation was found in the cluster\n\n"
+		summary.replaces["BACKUP_STORAGE_LOCATIONS"] = "❌ No BackupStorageLocation was found in the cluster"
+		summary.replaces["ERRORS"] += "⚠️ No BackupStorageLocation was found in the cluster\n\n"
 	}
 }
 
-func ReplaceVolumeSnapshotLocationsSection(outputPath string, volumeSnapshotLocationList *velerov1.VolumeSnapshotLocationList) {
+func (summary *Summary) ReplaceVolumeSnapshotLocationsSection(outputPath string, volumeSnapshotLocationList *velerov1.VolumeSnapshotLocationList) {
 	if volumeSnapshotLocationList != nil && len(volumeSnapshotLocationList.Items) != 0 {
 		volumeSnapshotLocationsByNamespace := map[string][]velerov1.VolumeSnapshotLocation{}
 
@@ -417,7 +552,7 @@ func ReplaceVolumeSnapshotLocationsSection(outputPath string, volumeSnapshotLoca
 			volumeSnapshotLocationsByNamespace[volumeSnapshotLocation.Namespace] = append(volumeSnapshotLocationsByNamespace[volumeSnapshotLocation.Namespace], volumeSnapshotLocation)
 		}
 
-		summaryTemplateReplaces["VOLUME_SNAPSHOT_LOCATIONS"] += "| Namespace | Name | yaml |\n| --- | --- | --- |\n"
+		summary.replaces["VOLUME_SNAPSHOT_LOCATIONS"] += "| Namespace | Name | yaml |\n| --- | --- | --- |\n"
 		for namespace, volumeSnapshotLocations := range volumeSnapshotLocationsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -429,20 +564,20 @@ func ReplaceVolumeSnapshotLocationsSection(outputPath string, volumeSnapshotLoca
 				list.Items = append(list.Items, runtime.RawExtension{Object: &volumeSnapshotLocation})
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["VOLUME_SNAPSHOT_LOCATIONS"] += fmt.Sprintf(
+				summary.replaces["VOLUME_SNAPSHOT_LOCATIONS"] += fmt.Sprintf(
 					"| %v | %v | %s |\n",
 					namespace, volumeSnapshotLocation.Name, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["VOLUME_SNAPSHOT_LOCATIONS"] = "❌ No VolumeSnapshotLocation was found in the cluster"
+		summary.replaces["VOLUME_SNAPSHOT_LOCATIONS"] = "❌ No VolumeSnapshotLocation was found in the cluster"
 	}
 }
 
-func ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, clusterClient client.Client, deleteBackupRequestList *velerov1.DeleteBackupRequestList, podVolumeBackupList *velerov1.PodVolumeBackupList) {
+func (summary *Summary) ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, clusterClient client.Client, deleteBackupRequestList *velerov1.DeleteBackupRequestList, podVolumeBackupList *velerov1.PodVolumeBackupList, relationshipIndex *gather.RelationshipIndex) {
 	if backupList != nil && len(backupList.Items) != 0 {
 		backupsByNamespace := map[string][]velerov1.Backup{}
 
@@ -450,7 +585,7 @@ func ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, c
 			backupsByNamespace[backup.Namespace] = append(backupsByNamespace[backup.Namespace], backup)
 		}
 
-		summaryTemplateReplaces["BACKUPS"] += "| Namespace | Name | status.phase | describe | logs | yaml |\n| --- | --- | --- | --- | --- | ---|\n"
+		summary.replaces["BACKUPS"] += "| Namespace | Name | status.phase | children | worst child | describe | logs | yaml |\n| --- | --- | --- | --- | --- | --- | --- | ---|\n"
 		for namespace, backups := range backupsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -465,7 +600,7 @@ func ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, c
 				backupStatusPhase := backup.Status.Phase
 				if len(backupStatusPhase) == 0 {
 					backupStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ Backup **%v** with **no status phase** in **%v** namespace\n\n",
 						backup.Name, namespace,
 					)
@@ -481,7 +616,7 @@ func ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, c
 						backupStatus = fmt.Sprintf("✅ status phase %s", backupStatusPhase)
 					} else if slices.Contains(failedStates, backupStatusPhase) {
 						backupStatus = fmt.Sprintf("❌ status phase %s", backupStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ Backup **%v** with **status phase %s** in **%v** namespace\n\n",
 							backup.Name, backupStatusPhase, namespace,
 						)
@@ -520,19 +655,22 @@ func ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, c
 					fmt.Println(err)
 					logs = fmt.Sprintf("❌ %s", err)
 				} else {
-					logs = createFile(
+					summary.addLogs("Backup "+backup.Namespace+"/"+backup.Name, writeTo.String())
+					logs = summary.createFile(
 						outputPath,
 						folder+"/"+backup.Name+".log",
 						writeTo.String(),
 						"logs",
 					)
 				}
+				childrenCount, worstChild := childrenText(relationshipIndex.BackupChildren(&backup))
 				yamlLink := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["BACKUPS"] += fmt.Sprintf(
-					"| %v | %v | %s | %s | %s | %s |\n",
+				summary.replaces["BACKUPS"] += fmt.Sprintf(
+					"| %v | %v | %s | %s | %s | %s | %s | %s |\n",
 					namespace, backup.Name,
 					backupStatus,
-					createFile(
+					childrenCount, worstChild,
+					summary.createFile(
 						outputPath,
 						folder+"/describe-"+backup.Name+".txt",
 						describeOutput,
@@ -543,14 +681,14 @@ func ReplaceBackupsSection(outputPath string, backupList *velerov1.BackupList, c
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["BACKUPS"] = "❌ No Backup was found in the cluster"
+		summary.replaces["BACKUPS"] = "❌ No Backup was found in the cluster"
 	}
 }
 
-func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.RestoreList, clusterClient client.Client, podVolumeRestoreList *velerov1.PodVolumeRestoreList) {
+func (summary *Summary) ReplaceRestoresSection(outputPath string, restoreListList *velerov1.RestoreList, clusterClient client.Client, podVolumeRestoreList *velerov1.PodVolumeRestoreList, relationshipIndex *gather.RelationshipIndex) {
 	if restoreListList != nil && len(restoreListList.Items) != 0 {
 		restoresByNamespace := map[string][]velerov1.Restore{}
 
@@ -558,7 +696,7 @@ func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.Restore
 			restoresByNamespace[restore.Namespace] = append(restoresByNamespace[restore.Namespace], restore)
 		}
 
-		summaryTemplateReplaces["RESTORES"] += "| Namespace | Name | status.phase | describe | logs | yaml |\n| --- | --- | --- | --- | --- | --- |\n"
+		summary.replaces["RESTORES"] += "| Namespace | Name | status.phase | children | worst child | describe | logs | yaml |\n| --- | --- | --- | --- | --- | --- | --- | --- |\n"
 		for namespace, restores := range restoresByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -573,7 +711,7 @@ func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.Restore
 				restoreStatusPhase := restore.Status.Phase
 				if len(restoreStatusPhase) == 0 {
 					restoreStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ Restore **%v** with **no status phase** in **%v** namespace\n\n",
 						restore.Name, namespace,
 					)
@@ -589,7 +727,7 @@ func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.Restore
 						restoreStatus = fmt.Sprintf("✅ status phase %s", restoreStatusPhase)
 					} else if slices.Contains(failedStates, restoreStatusPhase) {
 						restoreStatus = fmt.Sprintf("❌ status phase %s", restoreStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ Restore **%v** with **status phase %s** in **%v** namespace\n\n",
 							restore.Name, restoreStatusPhase, namespace,
 						)
@@ -621,7 +759,9 @@ func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.Restore
 					fmt.Println(err)
 					logs = fmt.Sprintf("❌ %s", err)
 				} else {
-					logs = createFile(
+					summary.addLogs("Restore "+restore.Namespace+"/"+restore.Name, writeTo.String())
+					summary.addRestoreLogs(restore.Namespace+"/"+restore.Name, writeTo.String())
+					logs = summary.createFile(
 						outputPath,
 						folder+"/"+restore.Name+".log",
 						writeTo.String(),
@@ -629,12 +769,14 @@ func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.Restore
 					)
 				}
 
+				childrenCount, worstChild := childrenText(relationshipIndex.RestoreChildren(&restore))
 				yamllink := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["RESTORES"] += fmt.Sprintf(
-					"| %v | %v | %s | %s | %s | %s |\n",
+				summary.replaces["RESTORES"] += fmt.Sprintf(
+					"| %v | %v | %s | %s | %s | %s | %s | %s |\n",
 					namespace, restore.Name,
 					restoreStatus,
-					createFile(
+					childrenCount, worstChild,
+					summary.createFile(
 						outputPath,
 						folder+"/describe-"+restore.Name+".txt",
 						describeOutput,
@@ -645,14 +787,14 @@ func ReplaceRestoresSection(outputPath string, restoreListList *velerov1.Restore
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["RESTORES"] = "❌ No Restore was found in the cluster"
+		summary.replaces["RESTORES"] = "❌ No Restore was found in the cluster"
 	}
 }
 
-func ReplaceSchedulesSection(outputPath string, scheduleList *velerov1.ScheduleList) {
+func (summary *Summary) ReplaceSchedulesSection(outputPath string, scheduleList *velerov1.ScheduleList) {
 	if scheduleList != nil && len(scheduleList.Items) != 0 {
 		schedulesByNamespace := map[string][]velerov1.Schedule{}
 
@@ -660,7 +802,7 @@ func ReplaceSchedulesSection(outputPath string, scheduleList *velerov1.ScheduleL
 			schedulesByNamespace[schedule.Namespace] = append(schedulesByNamespace[schedule.Namespace], schedule)
 		}
 
-		summaryTemplateReplaces["SCHEDULES"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		summary.replaces["SCHEDULES"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
 		for namespace, schedules := range schedulesByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -675,7 +817,7 @@ func ReplaceSchedulesSection(outputPath string, scheduleList *velerov1.ScheduleL
 				scheduleStatusPhase := schedule.Status.Phase
 				if len(scheduleStatusPhase) == 0 {
 					scheduleStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ Schedule **%v** with **no status phase** in **%v** namespace\n\n",
 						schedule.Name, namespace,
 					)
@@ -684,7 +826,7 @@ func ReplaceSchedulesSection(outputPath string, scheduleList *velerov1.ScheduleL
 						scheduleStatus = fmt.Sprintf("✅ status phase %s", scheduleStatusPhase)
 					} else if scheduleStatusPhase == velerov1.SchedulePhaseFailedValidation {
 						scheduleStatus = fmt.Sprintf("❌ status phase %s", scheduleStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ Schedule **%v** with **status phase %s** in **%v** namespace\n\n",
 							schedule.Name, scheduleStatusPhase, namespace,
 						)
@@ -694,34 +836,41 @@ func ReplaceSchedulesSection(outputPath string, scheduleList *velerov1.ScheduleL
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["SCHEDULES"] += fmt.Sprintf(
+				summary.replaces["SCHEDULES"] += fmt.Sprintf(
 					"| %v | %v | %s | %s |\n",
 					namespace, schedule.Name, scheduleStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["SCHEDULES"] = "❌ No Schedule was found in the cluster"
+		summary.replaces["SCHEDULES"] = "❌ No Schedule was found in the cluster"
 	}
 }
 
-func ReplaceBackupRepositoriesSection(outputPath string, backupRepositoryList *velerov1.BackupRepositoryList) {
+func (summary *Summary) ReplaceBackupRepositoriesSection(outputPath string, backupRepositoryList *velerov1.BackupRepositoryList, maintenanceJobList *batchv1.JobList, maintenancePodList *corev1.PodList, clientset kubernetes.Interface) {
 	if backupRepositoryList != nil && len(backupRepositoryList.Items) != 0 {
 		backupRepositoriesByNamespace := map[string][]velerov1.BackupRepository{}
+		// <namespace>/<BSL name> : number of BackupRepositories
+		backupRepositoriesByStorageLocation := map[string]int{}
 
 		for _, backupRepository := range backupRepositoryList.Items {
 			backupRepositoriesByNamespace[backupRepository.Namespace] = append(backupRepositoriesByNamespace[backupRepository.Namespace], backupRepository)
+			backupRepositoriesByStorageLocation[backupRepository.Namespace+"/"+backupRepository.Spec.BackupStorageLocation]++
 		}
 
-		summaryTemplateReplaces["BACKUPS_REPOSITORIES"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		now := time.Now()
+		summary.replaces["BACKUPS_REPOSITORIES"] += "| Namespace | Name | status.phase | spec.repositoryType | spec.backupStorageLocation | spec.volumeNamespace | spec.maintenanceFrequency | status.lastMaintenanceTime | maintenance jobs | yaml |\n| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |\n"
 		for namespace, backupRepositories := range backupRepositoriesByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
+			jobs := &corev1.List{}
+			jobs.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
 
 			folder := fmt.Sprintf("namespaces/%s/velero.io/backuprepositories", namespace)
 			file := folder + "/backuprepositories.yaml"
+			jobsFile := folder + "/maintenance-jobs.yaml"
 			for _, backupRepository := range backupRepositories {
 				backupRepository.GetObjectKind().SetGroupVersionKind(gvk.BackupRepositoryGVK)
 				list.Items = append(list.Items, runtime.RawExtension{Object: &backupRepository})
@@ -730,7 +879,7 @@ func ReplaceBackupRepositoriesSection(outputPath string, backupRepositoryList *v
 				backupRepositoryStatusPhase := backupRepository.Status.Phase
 				if len(backupRepositoryStatusPhase) == 0 {
 					backupRepositoryStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ BackupRepository **%v** with **no status phase** in **%v** namespace\n\n",
 						backupRepository.Name, namespace,
 					)
@@ -739,7 +888,7 @@ func ReplaceBackupRepositoriesSection(outputPath string, backupRepositoryList *v
 						backupRepositoryStatus = fmt.Sprintf("✅ status phase %s", backupRepositoryStatusPhase)
 					} else if backupRepositoryStatusPhase == velerov1.BackupRepositoryPhaseNotReady {
 						backupRepositoryStatus = fmt.Sprintf("❌ status phase %s", backupRepositoryStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ BackupRepository **%v** with **status phase %s** in **%v** namespace\n\n",
 							backupRepository.Name, backupRepositoryStatusPhase, namespace,
 						)
@@ -748,21 +897,120 @@ func ReplaceBackupRepositoriesSection(outputPath string, backupRepositoryList *v
 					}
 				}
 
+				maintenanceFrequency := backupRepository.Spec.MaintenanceFrequency.Duration
+				lastMaintenanceText := "never"
+				lastMaintenance := backupRepository.CreationTimestamp.Time
+				if backupRepository.Status.LastMaintenanceTime != nil {
+					lastMaintenance = backupRepository.Status.LastMaintenanceTime.Time
+					lastMaintenanceText = lastMaintenance.UTC().Format(time.RFC3339)
+				}
+				if maintenanceFrequency != 0 && now.Sub(lastMaintenance) > maintenanceOverdueFactor*maintenanceFrequency {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"⚠️ BackupRepository **%v** in **%v** namespace has **overdue maintenance**, last maintenance **%s** with maintenance frequency **%s**\n\n",
+						backupRepository.Name, namespace, lastMaintenanceText, maintenanceFrequency,
+					)
+					lastMaintenanceText = "⚠️ " + lastMaintenanceText
+				}
+
+				var repositoryJobs []batchv1.Job
+				if maintenanceJobList != nil {
+					for _, job := range maintenanceJobList.Items {
+						if job.Namespace == namespace && job.Labels[repository.RepositoryNameLabel] == backupRepository.Name {
+							repositoryJobs = append(repositoryJobs, job)
+						}
+					}
+				}
+				maintenanceJobsText := "-"
+				if len(repositoryJobs) != 0 {
+					slices.SortFunc(repositoryJobs, func(a, b batchv1.Job) int {
+						return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+					})
+					succeeded, failed := 0, 0
+					consecutiveFailures := 0
+					for _, job := range repositoryJobs {
+						job.GetObjectKind().SetGroupVersionKind(gvk.JobGVK)
+						jobs.Items = append(jobs.Items, runtime.RawExtension{Object: &job})
+						if job.Status.Failed > 0 {
+							failed++
+							consecutiveFailures++
+						} else if job.Status.Succeeded > 0 {
+							succeeded++
+							consecutiveFailures = 0
+						}
+					}
+					maintenanceJobsText = fmt.Sprintf("%d succeeded, %d failed", succeeded, failed)
+					if consecutiveFailures >= maintenanceFailuresThreshold {
+						maintenanceJobsText = "❌ " + maintenanceJobsText
+						summary.replaces["ERRORS"] += fmt.Sprintf(
+							"❌ BackupRepository **%v** in **%v** namespace had its last **%d** maintenance jobs failing\n\n",
+							backupRepository.Name, namespace, consecutiveFailures,
+						)
+					}
+					maintenanceJobsText += fmt.Sprintf("<br>[`yaml`](%s)", jobsFile)
+
+					if maintenancePodList != nil {
+						for _, pod := range maintenancePodList.Items {
+							if pod.Namespace != namespace || pod.Labels[repository.RepositoryNameLabel] != backupRepository.Name {
+								continue
+							}
+							for _, containerStatus := range pod.Status.ContainerStatuses {
+								if containerStatus.State.Terminated != nil && containerStatus.State.Terminated.ExitCode != 0 {
+									summary.replaces["ERRORS"] += fmt.Sprintf(
+										"❌ BackupRepository **%v** maintenance pod **%v** in **%v** namespace failed: %s\n\n",
+										backupRepository.Name, pod.Name, namespace, containerStatus.State.Terminated.Message,
+									)
+								}
+							}
+							logs, err := gather.PodLogs(clientset, &pod, "", 0)
+							if err != nil {
+								fmt.Println(err)
+								maintenanceJobsText += fmt.Sprintf("<br>❌ %s", err)
+							} else {
+								maintenanceJobsText += "<br>" + summary.createFile(
+									outputPath,
+									folder+"/maintenance-"+pod.Name+".log",
+									logs,
+									"logs "+pod.Name,
+								)
+							}
+						}
+					}
+				}
+
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["BACKUPS_REPOSITORIES"] += fmt.Sprintf(
-					"| %v | %v | %s | %s |\n",
-					namespace, backupRepository.Name, backupRepositoryStatus, link,
+				summary.replaces["BACKUPS_REPOSITORIES"] += fmt.Sprintf(
+					"| %v | %v | %s | %v | %v | %v | %s | %s | %s | %s |\n",
+					namespace, backupRepository.Name, backupRepositoryStatus,
+					backupRepository.Spec.RepositoryType,
+					backupRepository.Spec.BackupStorageLocation,
+					backupRepository.Spec.VolumeNamespace,
+					maintenanceFrequency,
+					lastMaintenanceText,
+					maintenanceJobsText,
+					link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
+			if len(jobs.Items) != 0 {
+				summary.createYAML(outputPath, jobsFile, jobs)
+			}
+		}
+
+		for storageLocation, count := range backupRepositoriesByStorageLocation {
+			if count >= backupRepositoriesPerStorageLocationThreshold {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ **%d** BackupRepositories share BackupStorageLocation **%v**, maintenance of all of them runs against the same bucket\n\n",
+					count, storageLocation,
+				)
+			}
 		}
 	} else {
-		summaryTemplateReplaces["BACKUPS_REPOSITORIES"] = "❌ No BackupRepository was found in the cluster"
+		summary.replaces["BACKUPS_REPOSITORIES"] = "❌ No BackupRepository was found in the cluster"
 	}
 }
 
-func ReplaceDataUploadsSection(outputPath string, dataUploadList *velerov2alpha1.DataUploadList) {
+func (summary *Summary) ReplaceDataUploadsSection(outputPath string, dataUploadList *velerov2alpha1.DataUploadList, backupList *velerov1.BackupList) {
 	if dataUploadList != nil && len(dataUploadList.Items) != 0 {
 		dataUploadByNamespace := map[string][]velerov2alpha1.DataUpload{}
 
@@ -770,7 +1018,8 @@ func ReplaceDataUploadsSection(outputPath string, dataUploadList *velerov2alpha1
 			dataUploadByNamespace[dataUpload.Namespace] = append(dataUploadByNamespace[dataUpload.Namespace], dataUpload)
 		}
 
-		summaryTemplateReplaces["DATA_UPLOADS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		now := time.Now()
+		summary.replaces["DATA_UPLOADS"] += "| Namespace | Name | Backup | status.phase | progress | elapsed | node | snapshot | yaml |\n| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n"
 		for namespace, dataUploads := range dataUploadByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -785,7 +1034,7 @@ func ReplaceDataUploadsSection(outputPath string, dataUploadList *velerov2alpha1
 				dataUploadStatusPhase := dataUpload.Status.Phase
 				if len(dataUploadStatusPhase) == 0 {
 					dataUploadStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ DataUpload **%v** with **no status phase** in **%v** namespace\n\n",
 						dataUpload.Name, namespace,
 					)
@@ -799,7 +1048,7 @@ func ReplaceDataUploadsSection(outputPath string, dataUploadList *velerov2alpha1
 						dataUploadStatus = fmt.Sprintf("✅ status phase %s", dataUploadStatusPhase)
 					} else if slices.Contains(failedStates, dataUploadStatusPhase) {
 						dataUploadStatus = fmt.Sprintf("❌ status phase %s", dataUploadStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ DataUpload **%v** with **status phase %s** in **%v** namespace\n\n",
 							dataUpload.Name, dataUploadStatusPhase, namespace,
 						)
@@ -808,21 +1057,76 @@ func ReplaceDataUploadsSection(outputPath string, dataUploadList *velerov2alpha1
 					}
 				}
 
+				elapsed := dataMoverElapsed(now, dataUpload.Status.StartTimestamp, dataUpload.Status.AcceptedTimestamp, dataUpload.CreationTimestamp, dataUpload.Status.CompletionTimestamp)
+				stalledStates := []velerov2alpha1.DataUploadPhase{
+					velerov2alpha1.DataUploadPhaseAccepted,
+					velerov2alpha1.DataUploadPhasePrepared,
+				}
+				if slices.Contains(stalledStates, dataUploadStatusPhase) && elapsed > dataMoverStallThreshold {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ DataUpload **%v** stuck in **status phase %s** for **%s** in **%v** namespace\n\n",
+						dataUpload.Name, dataUploadStatusPhase, elapsed.Round(time.Second), namespace,
+					)
+				}
+				if dataUploadStatusPhase == velerov2alpha1.DataUploadPhaseCanceled && isCanceledByNodeAgentRestart(dataUpload.Status.Message) {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ DataUpload **%v** in **%v** namespace was **canceled by a node-agent restart**: %s\n\n",
+						dataUpload.Name, namespace, dataUpload.Status.Message,
+					)
+				}
+
+				backupName := dataUpload.Labels[velerov1.BackupNameLabel]
+				for _, ownerReference := range dataUpload.OwnerReferences {
+					if ownerReference.Kind == gvk.BackupGVK.Kind {
+						backupName = ownerReference.Name
+					}
+				}
+				backupText := "-"
+				if len(backupName) != 0 {
+					backupText = backupName
+					foundBackup := false
+					if backupList != nil {
+						for _, backup := range backupList.Items {
+							if backup.Namespace == namespace && (backup.Name == backupName || label.GetValidName(backup.Name) == backupName) {
+								foundBackup = true
+								break
+							}
+						}
+					}
+					if !foundBackup {
+						backupText = fmt.Sprintf("❌ %s (not found)", backupName)
+						summary.replaces["ERRORS"] += fmt.Sprintf(
+							"❌ DataUpload **%v** in **%v** namespace belongs to Backup **%v**, which **no longer exists**\n\n",
+							dataUpload.Name, namespace, backupName,
+						)
+					}
+				}
+
+				snapshotText := "-"
+				if dataUpload.Spec.CSISnapshot != nil && len(dataUpload.Spec.CSISnapshot.VolumeSnapshot) != 0 {
+					snapshotText = fmt.Sprintf("%s/%s", dataUpload.Spec.SourceNamespace, dataUpload.Spec.CSISnapshot.VolumeSnapshot)
+				}
+
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["DATA_UPLOADS"] += fmt.Sprintf(
-					"| %v | %v | %s | %s |\n",
-					namespace, dataUpload.Name, dataUploadStatus, link,
+				summary.replaces["DATA_UPLOADS"] += fmt.Sprintf(
+					"| %v | %v | %v | %s | %s | %s | %s | %s | %s |\n",
+					namespace, dataUpload.Name, backupText, dataUploadStatus,
+					dataMoverProgressText(dataUpload.Status.Progress),
+					elapsed.Round(time.Second),
+					dataMoverNodeText(dataUpload.Status.Node, dataUpload.Status.AcceptedByNode),
+					snapshotText,
+					link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["DATA_UPLOADS"] = "❌ No DataUpload was found in the cluster"
+		summary.replaces["DATA_UPLOADS"] = "❌ No DataUpload was found in the cluster"
 	}
 }
 
-func ReplaceDataDownloadsSection(outputPath string, dataDownloadList *velerov2alpha1.DataDownloadList) {
+func (summary *Summary) ReplaceDataDownloadsSection(outputPath string, dataDownloadList *velerov2alpha1.DataDownloadList) {
 	if dataDownloadList != nil && len(dataDownloadList.Items) != 0 {
 		dataDownloadByNamespace := map[string][]velerov2alpha1.DataDownload{}
 
@@ -830,7 +1134,8 @@ func ReplaceDataDownloadsSection(outputPath string, dataDownloadList *velerov2al
 			dataDownloadByNamespace[dataDownload.Namespace] = append(dataDownloadByNamespace[dataDownload.Namespace], dataDownload)
 		}
 
-		summaryTemplateReplaces["DATA_DOWNLOADS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		now := time.Now()
+		summary.replaces["DATA_DOWNLOADS"] += "| Namespace | Name | status.phase | progress | elapsed | node | snapshot | yaml |\n| --- | --- | --- | --- | --- | --- | --- | --- |\n"
 		for namespace, dataDownloads := range dataDownloadByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -845,7 +1150,7 @@ func ReplaceDataDownloadsSection(outputPath string, dataDownloadList *velerov2al
 				dataDownloadStatusPhase := dataDownload.Status.Phase
 				if len(dataDownloadStatusPhase) == 0 {
 					dataDownloadStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ DataDownload **%v** with **no status phase** in **%v** namespace\n\n",
 						dataDownload.Name, namespace,
 					)
@@ -859,7 +1164,7 @@ func ReplaceDataDownloadsSection(outputPath string, dataDownloadList *velerov2al
 						dataDownloadStatus = fmt.Sprintf("✅ status phase %s", dataDownloadStatusPhase)
 					} else if slices.Contains(failedStates, dataDownloadStatusPhase) {
 						dataDownloadStatus = fmt.Sprintf("❌ status phase %s", dataDownloadStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ DataDownload **%v** with **status phase %s** in **%v** namespace\n\n",
 							dataDownload.Name, dataDownloadStatusPhase, namespace,
 						)
@@ -868,21 +1173,49 @@ func ReplaceDataDownloadsSection(outputPath string, dataDownloadList *velerov2al
 					}
 				}
 
+				elapsed := dataMoverElapsed(now, dataDownload.Status.StartTimestamp, dataDownload.Status.AcceptedTimestamp, dataDownload.CreationTimestamp, dataDownload.Status.CompletionTimestamp)
+				stalledStates := []velerov2alpha1.DataDownloadPhase{
+					velerov2alpha1.DataDownloadPhaseAccepted,
+					velerov2alpha1.DataDownloadPhasePrepared,
+				}
+				if slices.Contains(stalledStates, dataDownloadStatusPhase) && elapsed > dataMoverStallThreshold {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ DataDownload **%v** stuck in **status phase %s** for **%s** in **%v** namespace\n\n",
+						dataDownload.Name, dataDownloadStatusPhase, elapsed.Round(time.Second), namespace,
+					)
+				}
+				if dataDownloadStatusPhase == velerov2alpha1.DataDownloadPhaseCanceled && isCanceledByNodeAgentRestart(dataDownload.Status.Message) {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ DataDownload **%v** in **%v** namespace was **canceled by a node-agent restart**: %s\n\n",
+						dataDownload.Name, namespace, dataDownload.Status.Message,
+					)
+				}
+
+				snapshotText := "-"
+				if len(dataDownload.Spec.SnapshotID) != 0 {
+					snapshotText = dataDownload.Spec.SnapshotID
+				}
+
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["DATA_DOWNLOADS"] += fmt.Sprintf(
-					"| %v | %v | %s | %s |\n",
-					namespace, dataDownload.Name, dataDownloadStatus, link,
+				summary.replaces["DATA_DOWNLOADS"] += fmt.Sprintf(
+					"| %v | %v | %s | %s | %s | %s | %s | %s |\n",
+					namespace, dataDownload.Name, dataDownloadStatus,
+					dataMoverProgressText(dataDownload.Status.Progress),
+					elapsed.Round(time.Second),
+					dataMoverNodeText(dataDownload.Status.Node, dataDownload.Status.AcceptedByNode),
+					snapshotText,
+					link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["DATA_DOWNLOADS"] = "❌ No DataDownload was found in the cluster"
+		summary.replaces["DATA_DOWNLOADS"] = "❌ No DataDownload was found in the cluster"
 	}
 }
 
-func ReplacePodVolumeBackupsSection(outputPath string, podVolumeBackupList *velerov1.PodVolumeBackupList) {
+func (summary *Summary) ReplacePodVolumeBackupsSection(outputPath string, podVolumeBackupList *velerov1.PodVolumeBackupList) {
 	if podVolumeBackupList != nil && len(podVolumeBackupList.Items) != 0 {
 		podVolumeBackupsByNamespace := map[string][]velerov1.PodVolumeBackup{}
 
@@ -890,7 +1223,7 @@ func ReplacePodVolumeBackupsSection(outputPath string, podVolumeBackupList *vele
 			podVolumeBackupsByNamespace[podVolumeBackup.Namespace] = append(podVolumeBackupsByNamespace[podVolumeBackup.Namespace], podVolumeBackup)
 		}
 
-		summaryTemplateReplaces["POD_VOLUME_BACKUPS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		summary.replaces["POD_VOLUME_BACKUPS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
 		for namespace, podVolumeBackups := range podVolumeBackupsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -905,7 +1238,7 @@ func ReplacePodVolumeBackupsSection(outputPath string, podVolumeBackupList *vele
 				podVolumeBackupStatusPhase := podVolumeBackup.Status.Phase
 				if len(podVolumeBackupStatusPhase) == 0 {
 					podVolumeBackupStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ PodVolumeBackup **%v** with **no status phase** in **%v** namespace\n\n",
 						podVolumeBackup.Name, namespace,
 					)
@@ -914,7 +1247,7 @@ func ReplacePodVolumeBackupsSection(outputPath string, podVolumeBackupList *vele
 						podVolumeBackupStatus = fmt.Sprintf("✅ status phase %s", podVolumeBackupStatusPhase)
 					} else if podVolumeBackupStatusPhase == velerov1.PodVolumeBackupPhaseFailed {
 						podVolumeBackupStatus = fmt.Sprintf("❌ status phase %s", podVolumeBackupStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ PodVolumeBackup **%v** with **status phase %s** in **%v** namespace\n\n",
 							podVolumeBackup.Name, podVolumeBackupStatusPhase, namespace,
 						)
@@ -924,20 +1257,20 @@ func ReplacePodVolumeBackupsSection(outputPath string, podVolumeBackupList *vele
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["POD_VOLUME_BACKUPS"] += fmt.Sprintf(
+				summary.replaces["POD_VOLUME_BACKUPS"] += fmt.Sprintf(
 					"| %v | %v | %s | %s |\n",
 					namespace, podVolumeBackup.Name, podVolumeBackupStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["POD_VOLUME_BACKUPS"] = "❌ No PodVolumeBackup was found in the cluster"
+		summary.replaces["POD_VOLUME_BACKUPS"] = "❌ No PodVolumeBackup was found in the cluster"
 	}
 }
 
-func ReplacePodVolumeRestoresSection(outputPath string, podVolumeRestoreList *velerov1.PodVolumeRestoreList) {
+func (summary *Summary) ReplacePodVolumeRestoresSection(outputPath string, podVolumeRestoreList *velerov1.PodVolumeRestoreList) {
 	if podVolumeRestoreList != nil && len(podVolumeRestoreList.Items) != 0 {
 		podVolumeRestoresByNamespace := map[string][]velerov1.PodVolumeRestore{}
 
@@ -945,7 +1278,7 @@ func ReplacePodVolumeRestoresSection(outputPath string, podVolumeRestoreList *ve
 			podVolumeRestoresByNamespace[podVolumeRestore.Namespace] = append(podVolumeRestoresByNamespace[podVolumeRestore.Namespace], podVolumeRestore)
 		}
 
-		summaryTemplateReplaces["POD_VOLUME_RESTORES"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		summary.replaces["POD_VOLUME_RESTORES"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
 		for namespace, podVolumeRestores := range podVolumeRestoresByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -960,7 +1293,7 @@ func ReplacePodVolumeRestoresSection(outputPath string, podVolumeRestoreList *ve
 				podVolumeRestoreStatusPhase := podVolumeRestore.Status.Phase
 				if len(podVolumeRestoreStatusPhase) == 0 {
 					podVolumeRestoreStatus = "⚠️ no status phase"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ PodVolumeRestore **%v** with **no status phase** in **%v** namespace\n\n",
 						podVolumeRestore.Name, namespace,
 					)
@@ -969,7 +1302,7 @@ func ReplacePodVolumeRestoresSection(outputPath string, podVolumeRestoreList *ve
 						podVolumeRestoreStatus = fmt.Sprintf("✅ status phase %s", podVolumeRestoreStatusPhase)
 					} else if podVolumeRestoreStatusPhase == velerov1.PodVolumeRestorePhaseFailed {
 						podVolumeRestoreStatus = fmt.Sprintf("❌ status phase %s", podVolumeRestoreStatusPhase)
-						summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+						summary.replaces["ERRORS"] += fmt.Sprintf(
 							"❌ PodVolumeRestore **%v** with **status phase %s** in **%v** namespace\n\n",
 							podVolumeRestore.Name, podVolumeRestoreStatusPhase, namespace,
 						)
@@ -979,20 +1312,86 @@ func ReplacePodVolumeRestoresSection(outputPath string, podVolumeRestoreList *ve
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["POD_VOLUME_RESTORES"] += fmt.Sprintf(
+				summary.replaces["POD_VOLUME_RESTORES"] += fmt.Sprintf(
 					"| %v | %v | %s | %s |\n",
 					namespace, podVolumeRestore.Name, podVolumeRestoreStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["POD_VOLUME_RESTORES"] = "❌ No PodVolumeRestore was found in the cluster"
+		summary.replaces["POD_VOLUME_RESTORES"] = "❌ No PodVolumeRestore was found in the cluster"
 	}
 }
 
-func ReplaceDownloadRequestsSection(outputPath string, downloadRequestList *velerov1.DownloadRequestList) {
+func (summary *Summary) ReplaceVolumeSnapshotsSection(outputPath string, volumeSnapshotList *volumesnapshotv1.VolumeSnapshotList) {
+	volumeSnapshotsByNamespace := map[string][]volumesnapshotv1.VolumeSnapshot{}
+	if volumeSnapshotList != nil {
+		for _, volumeSnapshot := range volumeSnapshotList.Items {
+			_, isBackupVolumeSnapshot := volumeSnapshot.Labels[velerov1.BackupNameLabel]
+			_, isRestoreVolumeSnapshot := volumeSnapshot.Labels[velerov1.RestoreNameLabel]
+			if isBackupVolumeSnapshot || isRestoreVolumeSnapshot {
+				volumeSnapshotsByNamespace[volumeSnapshot.Namespace] = append(volumeSnapshotsByNamespace[volumeSnapshot.Namespace], volumeSnapshot)
+			}
+		}
+	}
+
+	if len(volumeSnapshotsByNamespace) != 0 {
+		summary.replaces["VOLUME_SNAPSHOTS"] += "| Namespace | Name | Backup | Restore | status.readyToUse | yaml |\n| --- | --- | --- | --- | --- | --- |\n"
+		for namespace, volumeSnapshots := range volumeSnapshotsByNamespace {
+			list := &corev1.List{}
+			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
+
+			folder := fmt.Sprintf("namespaces/%s/snapshot.storage.k8s.io/volumesnapshots", namespace)
+			file := folder + "/volumesnapshots.yaml"
+			for _, volumeSnapshot := range volumeSnapshots {
+				volumeSnapshot.GetObjectKind().SetGroupVersionKind(gvk.VolumeSnapshotGVK)
+				list.Items = append(list.Items, runtime.RawExtension{Object: &volumeSnapshot})
+
+				volumeSnapshotStatus := ""
+				if volumeSnapshot.Status == nil {
+					volumeSnapshotStatus = "⚠️ no status"
+				} else if volumeSnapshot.Status.Error != nil {
+					errorMessage := ""
+					if volumeSnapshot.Status.Error.Message != nil {
+						errorMessage = *volumeSnapshot.Status.Error.Message
+					}
+					volumeSnapshotStatus = "❌ error"
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ VolumeSnapshot **%v** with **error** in **%v** namespace: %s\n\n",
+						volumeSnapshot.Name, namespace, errorMessage,
+					)
+				} else if volumeSnapshot.Status.ReadyToUse != nil && *volumeSnapshot.Status.ReadyToUse {
+					volumeSnapshotStatus = "✅ true"
+				} else {
+					volumeSnapshotStatus = "⚠️ false"
+				}
+
+				backupName := volumeSnapshot.Labels[velerov1.BackupNameLabel]
+				if len(backupName) == 0 {
+					backupName = "-"
+				}
+				restoreName := volumeSnapshot.Labels[velerov1.RestoreNameLabel]
+				if len(restoreName) == 0 {
+					restoreName = "-"
+				}
+
+				link := fmt.Sprintf("[`yaml`](%s)", file)
+				summary.replaces["VOLUME_SNAPSHOTS"] += fmt.Sprintf(
+					"| %v | %v | %v | %v | %s | %s |\n",
+					namespace, volumeSnapshot.Name, backupName, restoreName, volumeSnapshotStatus, link,
+				)
+			}
+
+			summary.createYAML(outputPath, file, list)
+		}
+	} else {
+		summary.replaces["VOLUME_SNAPSHOTS"] = "❌ No VolumeSnapshot created by Velero was found in the cluster"
+	}
+}
+
+func (summary *Summary) ReplaceDownloadRequestsSection(outputPath string, downloadRequestList *velerov1.DownloadRequestList) {
 	if downloadRequestList != nil && len(downloadRequestList.Items) != 0 {
 		downloadRequestsByNamespace := map[string][]velerov1.DownloadRequest{}
 
@@ -1000,7 +1399,7 @@ func ReplaceDownloadRequestsSection(outputPath string, downloadRequestList *vele
 			downloadRequestsByNamespace[downloadRequest.Namespace] = append(downloadRequestsByNamespace[downloadRequest.Namespace], downloadRequest)
 		}
 
-		summaryTemplateReplaces["DOWNLOAD_REQUESTS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		summary.replaces["DOWNLOAD_REQUESTS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
 		for namespace, downloadRequests := range downloadRequestsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -1015,7 +1414,7 @@ func ReplaceDownloadRequestsSection(outputPath string, downloadRequestList *vele
 				downloadRequestStatusPhase := downloadRequest.Status.Phase
 				if len(downloadRequestStatusPhase) == 0 {
 					downloadRequestStatus = "⚠️ no status"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ DownloadRequest **%v** with **no status** in **%v** namespace\n\n",
 						downloadRequest.Name, namespace,
 					)
@@ -1028,20 +1427,20 @@ func ReplaceDownloadRequestsSection(outputPath string, downloadRequestList *vele
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["DOWNLOAD_REQUESTS"] += fmt.Sprintf(
+				summary.replaces["DOWNLOAD_REQUESTS"] += fmt.Sprintf(
 					"| %v | %v | %s | %s |\n",
 					namespace, downloadRequest.Name, downloadRequestStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["DOWNLOAD_REQUESTS"] = "❌ No DownloadRequest was found in the cluster"
+		summary.replaces["DOWNLOAD_REQUESTS"] = "❌ No DownloadRequest was found in the cluster"
 	}
 }
 
-func ReplaceDeleteBackupRequestsSection(outputPath string, deleteBackupRequestList *velerov1.DeleteBackupRequestList) {
+func (summary *Summary) ReplaceDeleteBackupRequestsSection(outputPath string, deleteBackupRequestList *velerov1.DeleteBackupRequestList) {
 	if deleteBackupRequestList != nil && len(deleteBackupRequestList.Items) != 0 {
 		deleteBackupRequestsByNamespace := map[string][]velerov1.DeleteBackupRequest{}
 
@@ -1049,7 +1448,7 @@ func ReplaceDeleteBackupRequestsSection(outputPath string, deleteBackupRequestLi
 			deleteBackupRequestsByNamespace[deleteBackupRequest.Namespace] = append(deleteBackupRequestsByNamespace[deleteBackupRequest.Namespace], deleteBackupRequest)
 		}
 
-		summaryTemplateReplaces["DELETE_BACKUP_REQUESTS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		summary.replaces["DELETE_BACKUP_REQUESTS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
 		for namespace, deleteBackupRequests := range deleteBackupRequestsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -1064,7 +1463,7 @@ func ReplaceDeleteBackupRequestsSection(outputPath string, deleteBackupRequestLi
 				deleteBackupRequestStatusPhase := deleteBackupRequest.Status.Phase
 				if len(deleteBackupRequestStatusPhase) == 0 {
 					deleteBackupRequestStatus = "⚠️ no status"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ DeleteBackupRequest **%v** with **no status** in **%v** namespace\n\n",
 						deleteBackupRequest.Name, namespace,
 					)
@@ -1077,20 +1476,20 @@ func ReplaceDeleteBackupRequestsSection(outputPath string, deleteBackupRequestLi
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["DELETE_BACKUP_REQUESTS"] += fmt.Sprintf(
+				summary.replaces["DELETE_BACKUP_REQUESTS"] += fmt.Sprintf(
 					"| %v | %v | %s | %s |\n",
 					namespace, deleteBackupRequest.Name, deleteBackupRequestStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["DELETE_BACKUP_REQUESTS"] = "❌ No DeleteBackupRequest was found in the cluster"
+		summary.replaces["DELETE_BACKUP_REQUESTS"] = "❌ No DeleteBackupRequest was found in the cluster"
 	}
 }
 
-func ReplaceServerStatusRequestsSection(outputPath string, serverStatusRequestList *velerov1.ServerStatusRequestList) {
+func (summary *Summary) ReplaceServerStatusRequestsSection(outputPath string, serverStatusRequestList *velerov1.ServerStatusRequestList) {
 	if serverStatusRequestList != nil && len(serverStatusRequestList.Items) != 0 {
 		serverStatusRequestsByNamespace := map[string][]velerov1.ServerStatusRequest{}
 
@@ -1098,7 +1497,7 @@ func ReplaceServerStatusRequestsSection(outputPath string, serverStatusRequestLi
 			serverStatusRequestsByNamespace[serverStatusRequest.Namespace] = append(serverStatusRequestsByNamespace[serverStatusRequest.Namespace], serverStatusRequest)
 		}
 
-		summaryTemplateReplaces["SERVER_STATUS_REQUESTS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
+		summary.replaces["SERVER_STATUS_REQUESTS"] += "| Namespace | Name | status.phase | yaml |\n| --- | --- | --- | --- |\n"
 		for namespace, serverStatusRequests := range serverStatusRequestsByNamespace {
 			list := &corev1.List{}
 			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -1113,7 +1512,7 @@ func ReplaceServerStatusRequestsSection(outputPath string, serverStatusRequestLi
 				serverStatusRequestStatusPhase := serverStatusRequest.Status.Phase
 				if len(serverStatusRequestStatusPhase) == 0 {
 					serverStatusRequestStatus = "⚠️ no status"
-					summaryTemplateReplaces["ERRORS"] += fmt.Sprintf(
+					summary.replaces["ERRORS"] += fmt.Sprintf(
 						"⚠️ ServerStatusRequest **%v** with **no status** in **%v** namespace\n\n",
 						serverStatusRequest.Name, namespace,
 					)
@@ -1126,16 +1525,16 @@ func ReplaceServerStatusRequestsSection(outputPath string, serverStatusRequestLi
 				}
 
 				link := fmt.Sprintf("[`yaml`](%s)", file)
-				summaryTemplateReplaces["SERVER_STATUS_REQUESTS"] += fmt.Sprintf(
+				summary.replaces["SERVER_STATUS_REQUESTS"] += fmt.Sprintf(
 					"| %v | %v | %s | %s |\n",
 					namespace, serverStatusRequest.Name, serverStatusRequestStatus, link,
 				)
 			}
 
-			createYAML(outputPath, file, list)
+			summary.createYAML(outputPath, file, list)
 		}
 	} else {
-		summaryTemplateReplaces["SERVER_STATUS_REQUESTS"] = "❌ No ServerStatusRequest was found in the cluster"
+		summary.replaces["SERVER_STATUS_REQUESTS"] = "❌ No ServerStatusRequest was found in the cluster"
 	}
 }
 
@@ -1143,7 +1542,7 @@ func ReplaceServerStatusRequestsSection(outputPath string, serverStatusRequestLi
 
 // TODO this function writes summary and cluster files
 // break into 2
-func ReplaceAvailableStorageClassesSection(outputPath string, storageClassList *storagev1.StorageClassList) {
+func (summary *Summary) ReplaceAvailableStorageClassesSection(outputPath string, storageClassList *storagev1.StorageClassList) {
 	if storageClassList != nil && len(storageClassList.Items) != 0 {
 		list := &corev1.List{}
 		list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -1154,14 +1553,14 @@ func ReplaceAvailableStorageClassesSection(outputPath string, storageClassList *
 		}
 		// TODO could not create generic function, type/interface/pointer error
 		// createYAMLList(storageClassList, gvk.StorageClassGVK)
-		summaryTemplateReplaces["STORAGE_CLASSES"] = createYAML(outputPath, "cluster-scoped-resources/storage.k8s.io/storageclasses/storageclasses.yaml", list)
+		summary.replaces["STORAGE_CLASSES"] = summary.createYAML(outputPath, "cluster-scoped-resources/storage.k8s.io/storageclasses/storageclasses.yaml", list)
 	} else {
-		summaryTemplateReplaces["STORAGE_CLASSES"] = "❌ No StorageClass was found in the cluster"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No StorageClass was found in the cluster\n\n"
+		summary.replaces["STORAGE_CLASSES"] = "❌ No StorageClass was found in the cluster"
+		summary.replaces["ERRORS"] += "⚠️ No StorageClass was found in the cluster\n\n"
 	}
 }
 
-func ReplaceAvailableVolumeSnapshotClassesSection(outputPath string, volumeSnapshotClassList *volumesnapshotv1.VolumeSnapshotClassList) {
+func (summary *Summary) ReplaceAvailableVolumeSnapshotClassesSection(outputPath string, volumeSnapshotClassList *volumesnapshotv1.VolumeSnapshotClassList) {
 	if volumeSnapshotClassList != nil && len(volumeSnapshotClassList.Items) != 0 {
 		list := &corev1.List{}
 		list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -1170,14 +1569,14 @@ func ReplaceAvailableVolumeSnapshotClassesSection(outputPath string, volumeSnaps
 			volumeSnapshotClass.GetObjectKind().SetGroupVersionKind(gvk.VolumeSnapshotClassGVK)
 			list.Items = append(list.Items, runtime.RawExtension{Object: &volumeSnapshotClass})
 		}
-		summaryTemplateReplaces["VOLUME_SNAPSHOT_CLASSES"] = createYAML(outputPath, "cluster-scoped-resources/snapshot.storage.k8s.io/volumesnapshotclasses/volumesnapshotclasses.yaml", list)
+		summary.replaces["VOLUME_SNAPSHOT_CLASSES"] = summary.createYAML(outputPath, "cluster-scoped-resources/snapshot.storage.k8s.io/volumesnapshotclasses/volumesnapshotclasses.yaml", list)
 	} else {
-		summaryTemplateReplaces["VOLUME_SNAPSHOT_CLASSES"] = "❌ No VolumeSnapshotClass was found in the cluster"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No VolumeSnapshotClass was found in the cluster\n\n"
+		summary.replaces["VOLUME_SNAPSHOT_CLASSES"] = "❌ No VolumeSnapshotClass was found in the cluster"
+		summary.replaces["ERRORS"] += "⚠️ No VolumeSnapshotClass was found in the cluster\n\n"
 	}
 }
 
-func ReplaceAvailableCSIDriversSection(outputPath string, csiDriverList *storagev1.CSIDriverList, oadpOpenShiftVersion string) {
+func (summary *Summary) ReplaceAvailableCSIDriversSection(outputPath string, csiDriverList *storagev1.CSIDriverList, oadpOpenShiftVersion string) {
 	if csiDriverList != nil && len(csiDriverList.Items) != 0 {
 		list := &corev1.List{}
 		list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
@@ -1186,15 +1585,19 @@ func ReplaceAvailableCSIDriversSection(outputPath string, csiDriverList *storage
 			csiDriver.GetObjectKind().SetGroupVersionKind(gvk.CSIDriverGVK)
 			list.Items = append(list.Items, runtime.RawExtension{Object: &csiDriver})
 		}
-		summaryTemplateReplaces["CSI_DRIVERS"] = createYAML(outputPath, "cluster-scoped-resources/storage.k8s.io/csidrivers/csidrivers.yaml", list)
+		summary.replaces["CSI_DRIVERS"] = summary.createYAML(outputPath, "cluster-scoped-resources/storage.k8s.io/csidrivers/csidrivers.yaml", list)
 	} else {
-		summaryTemplateReplaces["CSI_DRIVERS"] = "❌ No CSIDriver was found in the cluster"
-		summaryTemplateReplaces["ERRORS"] += "⚠️ No CSIDriver was found in the cluster\n\n"
+		summary.replaces["CSI_DRIVERS"] = "❌ No CSIDriver was found in the cluster"
+		summary.replaces["ERRORS"] += "⚠️ No CSIDriver was found in the cluster\n\n"
 	}
-	summaryTemplateReplaces["OADP_OCP_VERSION"] = oadpOpenShiftVersion
+	// OpenShift documentation of latest version, if cluster version is unknown
+	if len(oadpOpenShiftVersion) == 0 {
+		oadpOpenShiftVersion = "latest"
+	}
+	summary.replaces["OADP_OCP_VERSION"] = oadpOpenShiftVersion
 }
 
-func ReplaceCustomResourceDefinitionsSection(outputPath string, clusterConfig *rest.Config) {
+func (summary *Summary) ReplaceCustomResourceDefinitionsSection(outputPath string, clusterConfig *rest.Config) {
 	// TODO error!!!
 	client, _ := apiextensionsclientset.NewForConfig(clusterConfig)
 
@@ -1225,14 +1628,14 @@ func ReplaceCustomResourceDefinitionsSection(outputPath string, clusterConfig *r
 		crd, _ := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName+"."+crdGroup, v1.GetOptions{})
 		crd.GetObjectKind().SetGroupVersionKind(gvk.CustomResourceDefinitionGVK)
 		// TODO check error
-		createYAML(outputPath, crdsPath+fmt.Sprintf("/%s.yaml", crdName), crd)
+		summary.createYAML(outputPath, crdsPath+fmt.Sprintf("/%s.yaml", crdName), crd)
 	}
 
-	summaryTemplateReplaces["CUSTOM_RESOURCE_DEFINITION"] = fmt.Sprintf("For more information, check [`%s`](%s)\n\n", crdsPath, crdsPath)
+	summary.replaces["CUSTOM_RESOURCE_DEFINITION"] = fmt.Sprintf("For more information, check [`%s`](%s)\n\n", crdsPath, crdsPath)
 }
 
 // TODO move to another folder?
-func createYAML(outputPath string, yamlPath string, obj runtime.Object) string {
+func (summary *Summary) createYAML(outputPath string, yamlPath string, obj runtime.Object) string {
 	objFilePath := outputPath + yamlPath
 	dir := path.Dir(objFilePath)
 	// TODO permission
@@ -1251,8 +1654,11 @@ func createYAML(outputPath string, yamlPath string, obj runtime.Object) string {
 		err = printer.PrintObj(obj, newFile)
 		if err != nil {
 			fmt.Println(err)
+			manifest.RecordFailure(objFilePath, manifest.APICall(obj), err)
 			result = "❌ Unable to write " + objFilePath
 		} else {
+			manifest.RecordFile(objFilePath, manifest.APICall(obj))
+			summary.addWrittenSize(objFilePath)
 			result = fmt.Sprintf("For more information, check [`%s`](%s)\n\n", yamlPath, yamlPath)
 		}
 	}
@@ -1260,8 +1666,9 @@ func createYAML(outputPath string, yamlPath string, obj runtime.Object) string {
 	return result
 }
 
-func createFile(outputPath string, describePath string, describeOutput string, describeTitle string) string {
+func (summary *Summary) createFile(outputPath string, describePath string, describeOutput string, describeTitle string) string {
 	describeFilePath := outputPath + describePath
+	isLogs := strings.HasPrefix(describeTitle, "logs")
 	dir := path.Dir(describeFilePath)
 	// TODO permission
 	// TODO need defer somewhere?
@@ -1278,8 +1685,16 @@ func createFile(outputPath string, describePath string, describeOutput string, d
 		err := os.WriteFile(describeFilePath, []byte(describeOutput), 0644)
 		if err != nil {
 			fmt.Println(err)
+			manifest.RecordFailure(describeFilePath, describeTitle, err)
 			result = "❌ Unable to write " + describeFilePath
 		} else {
+			manifest.RecordFile(describeFilePath, describeTitle)
+			if isLogs && sizeBudget != 0 {
+				// logs are fit in size budget after all other files are written, by BudgetLogs
+				summary.logsFiles = append(summary.logsFiles, logsFile{outputPath: outputPath, path: describePath})
+			} else {
+				summary.addWrittenSize(describeFilePath)
+			}
 			result = fmt.Sprintf("[`"+describeTitle+"`](%s)", describePath)
 		}
 	}
@@ -1287,22 +1702,22 @@ func createFile(outputPath string, describePath string, describeOutput string, d
 	return result
 }
 
-func Write(outputPath string) error {
-	if len(summaryTemplateReplaces["ERRORS"]) == 0 {
-		summaryTemplateReplaces["ERRORS"] += "No errors happened or were found while running OADP must-gather\n\n"
+func (summary *Summary) Write(outputPath string) error {
+	if len(summary.replaces["ERRORS"]) == 0 {
+		summary.replaces["ERRORS"] += "No errors happened or were found while running OADP must-gather\n\n"
 	}
 
-	summary := summaryTemplate
+	summaryText := summaryTemplate
 	for _, key := range summaryTemplateReplacesKeys {
-		value, ok := summaryTemplateReplaces[key]
+		value, ok := summary.replaces[key]
 		if !ok {
 			return fmt.Errorf("key '%s' not set in SummaryTemplateReplaces", key)
 		}
 		if len(value) == 0 {
 			return fmt.Errorf("value for key '%s' not set in SummaryTemplateReplaces", key)
 		}
-		summary = strings.ReplaceAll(
-			summary,
+		summaryText = strings.ReplaceAll(
+			summaryText,
 			fmt.Sprintf("<<%s>>", key),
 			value,
 		)
@@ -1311,10 +1726,11 @@ func Write(outputPath string) error {
 	summaryPath := outputPath + "oadp-must-gather-summary.md"
 	// TODO permission
 	// TODO need defer somewhere?
-	err := os.WriteFile(summaryPath, []byte(summary), 0644)
+	err := os.WriteFile(summaryPath, []byte(summaryText), 0644)
 	if err != nil {
 		return err
 	}
+	manifest.RecordFile(summaryPath, "summary")
 
 	return nil
 }
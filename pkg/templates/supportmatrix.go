@@ -0,0 +1,111 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	openshiftconfigv1 "github.com/openshift/api/config/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/supportmatrix"
+)
+
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	lastSlash := strings.LastIndex(image, "/")
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon <= lastSlash {
+		return "latest"
+	}
+	return image[lastColon+1:]
+}
+
+func (summary *Summary) ReplaceSupportMatrixSection(
+	supportMatrix *supportmatrix.SupportMatrix,
+	importantCSVsByNamespace map[string][]operatorsv1alpha1.ClusterServiceVersion,
+	clusterVersion *openshiftconfigv1.ClusterVersion,
+	veleroDeploymentList *appsv1.DeploymentList,
+) {
+	source := "embedded in OADP must-gather"
+	if len(supportMatrix.Source) != 0 {
+		source = fmt.Sprintf("from `%s`", supportMatrix.Source)
+	}
+	summary.replaces["SUPPORT_MATRIX"] = fmt.Sprintf("Support matrix version `%s` %s\n\n", supportMatrix.Version, source)
+
+	openShiftVersion := clusterVersion.Status.Desired.Version
+	if _, ok := supportMatrix.OpenShiftRelease(openShiftVersion); !ok {
+		summary.replaces["ERRORS"] += fmt.Sprintf(
+			"⚠️ OpenShift version **%v** is not in OADP must-gather support matrix, update it or pass a newer one with `--support-matrix`\n\n",
+			openShiftVersion,
+		)
+	}
+
+	foundOADP := false
+	summary.replaces["SUPPORT_MATRIX"] += "| Namespace | OADP version | supported OpenShift versions | bundled Velero version | OpenShift version | status |\n| --- | --- | --- | --- | --- | --- |\n"
+	for _, namespace := range slices.Sorted(maps.Keys(importantCSVsByNamespace)) {
+		for _, csv := range importantCSVsByNamespace[namespace] {
+			if csv.Spec.DisplayName != "OADP Operator" {
+				continue
+			}
+			foundOADP = true
+			oadpVersion := csv.Spec.Version.String()
+
+			release, ok := supportMatrix.OADPRelease(oadpVersion)
+			if !ok {
+				summary.replaces["SUPPORT_MATRIX"] += fmt.Sprintf(
+					"| %v | %v | - | - | %v | ⚠️ OADP version not in support matrix |\n",
+					namespace, oadpVersion, openShiftVersion,
+				)
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ OADP version **%v** in **%v** namespace is not in OADP must-gather support matrix\n\n",
+					oadpVersion, namespace,
+				)
+				continue
+			}
+
+			status := "✅ supported"
+			if !release.SupportsOpenShift(openShiftVersion) {
+				status = "❌ unsupported"
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ OADP version **%v** in **%v** namespace is **not supported** on OpenShift version **%v**, supported versions are %v\n\n",
+					oadpVersion, namespace, openShiftVersion, release.OpenShift,
+				)
+			}
+			summary.replaces["SUPPORT_MATRIX"] += fmt.Sprintf(
+				"| %v | %v | %v | %v | %v | %s |\n",
+				namespace, oadpVersion, strings.Join(release.OpenShift, ", "), release.Velero, openShiftVersion, status,
+			)
+
+			if veleroDeploymentList == nil {
+				continue
+			}
+			for _, deployment := range veleroDeploymentList.Items {
+				if deployment.Namespace != namespace {
+					continue
+				}
+				for _, container := range deployment.Spec.Template.Spec.InitContainers {
+					supportedTag, isKnownPlugin := release.Plugins[container.Name]
+					tag := imageTag(container.Image)
+					// images referenced by digest can not be compared
+					if !isKnownPlugin || len(tag) == 0 {
+						continue
+					}
+					if tag != supportedTag {
+						summary.replaces["ERRORS"] += fmt.Sprintf(
+							"⚠️ Velero plugin **%v** in **%v** namespace uses image tag **%v**, OADP **%v** supports tag **%v**\n\n",
+							container.Name, namespace, tag, release.Version, supportedTag,
+						)
+					}
+				}
+			}
+		}
+	}
+	if !foundOADP {
+		summary.replaces["SUPPORT_MATRIX"] = fmt.Sprintf("Support matrix version `%s` %s\n\n❌ No OADP Operator was found installed in the cluster", supportMatrix.Version, source)
+	}
+}
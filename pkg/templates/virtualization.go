@@ -0,0 +1,264 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	oadpv1alpha1 "github.com/openshift/oadp-operator/api/v1alpha1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gather"
+	"github.com/mateusoliveira43/oadp-must-gather/pkg/gvk"
+)
+
+const (
+	preBackupHookAnnotation   = "pre.hook.backup.velero.io/command"
+	postBackupHookAnnotation  = "post.hook.backup.velero.io/command"
+	fsBackupVolumesAnnotation = "backup.velero.io/backup-volumes"
+	virtualMachineNameLabel   = "vm.kubevirt.io/name"
+)
+
+// DataVolume phases without data being imported
+var dataVolumeCompletePhases = []string{"Succeeded", "WaitForFirstConsumer", "PendingPopulation"}
+
+// virtualMachineClaimNames returns PVC names used by a VirtualMachine, DataVolumes create PVCs with their names
+func virtualMachineClaimNames(virtualMachine *unstructured.Unstructured) []string {
+	volumes, _, _ := unstructured.NestedSlice(virtualMachine.Object, "spec", "template", "spec", "volumes")
+	var claimNames []string
+	for _, volume := range volumes {
+		volumeMap, ok := volume.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if claimName, found, _ := unstructured.NestedString(volumeMap, "persistentVolumeClaim", "claimName"); found {
+			claimNames = append(claimNames, claimName)
+		}
+		if dataVolumeName, found, _ := unstructured.NestedString(volumeMap, "dataVolume", "name"); found {
+			claimNames = append(claimNames, dataVolumeName)
+		}
+	}
+	return claimNames
+}
+
+// backupHasFreezeHooks returns true if Backup has pre and post hooks for a namespace, or virt-launcher pod is annotated with them
+func backupHasFreezeHooks(backup *velerov1.Backup, namespace string, virtLauncherPod *corev1.Pod) bool {
+	if virtLauncherPod != nil && len(virtLauncherPod.Annotations[preBackupHookAnnotation]) != 0 && len(virtLauncherPod.Annotations[postBackupHookAnnotation]) != 0 {
+		return true
+	}
+	for _, hook := range backup.Spec.Hooks.Resources {
+		if slices.Contains(hook.ExcludedNamespaces, namespace) {
+			continue
+		}
+		if len(hook.IncludedNamespaces) != 0 && !slices.Contains(hook.IncludedNamespaces, namespace) && !slices.Contains(hook.IncludedNamespaces, "*") {
+			continue
+		}
+		if len(hook.PreHooks) != 0 && len(hook.PostHooks) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (summary *Summary) unstructuredYAML(outputPath string, namespace string, group string, resource string, items []unstructured.Unstructured) string {
+	list := &corev1.List{}
+	list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
+	for _, item := range items {
+		list.Items = append(list.Items, runtime.RawExtension{Object: &item})
+	}
+	file := fmt.Sprintf("namespaces/%s/%s/%s/%s.yaml", namespace, group, resource, resource)
+	summary.createYAML(outputPath, file, list)
+	return fmt.Sprintf("[`%s.yaml`](%s)", resource, file)
+}
+
+func (summary *Summary) ReplaceVirtualizationSection(
+	outputPath string,
+	foundVirtualization bool,
+	dataProtectionApplicationList *oadpv1alpha1.DataProtectionApplicationList,
+	backupList *velerov1.BackupList,
+	virtualMachineList *unstructured.UnstructuredList,
+	virtualMachineInstanceList *unstructured.UnstructuredList,
+	dataVolumeList *unstructured.UnstructuredList,
+	persistentVolumeClaimList *corev1.PersistentVolumeClaimList,
+	virtLauncherPodList *corev1.PodList,
+) {
+	if !foundVirtualization {
+		summary.replaces["VIRTUALIZATION"] = "OpenShift Virtualization was not found installed in the cluster"
+		return
+	}
+
+	if virtualMachineList == nil || len(virtualMachineList.Items) == 0 {
+		summary.replaces["VIRTUALIZATION"] = "No VirtualMachine was found in namespaces covered by Backups"
+		return
+	}
+
+	// kubevirt plugin is only needed if Backups include VirtualMachines
+	backedUpVirtualMachines := slices.ContainsFunc(virtualMachineList.Items, func(virtualMachine unstructured.Unstructured) bool {
+		return backupList != nil && slices.ContainsFunc(backupList.Items, func(backup velerov1.Backup) bool {
+			return gather.BackupIncludesNamespace(&backup, virtualMachine.GetNamespace())
+		})
+	})
+	if backedUpVirtualMachines && dataProtectionApplicationList != nil {
+		for _, dataProtectionApplication := range dataProtectionApplicationList.Items {
+			configuration := dataProtectionApplication.Spec.Configuration
+			if configuration == nil || configuration.Velero == nil || !slices.Contains(configuration.Velero.DefaultPlugins, oadpv1alpha1.DefaultPluginKubeVirt) {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"❌ DataProtectionApplication **%v** in **%v** namespace does not have **%s** default plugin, VirtualMachines will not be properly backed up\n\n",
+					dataProtectionApplication.Name, dataProtectionApplication.Namespace, oadpv1alpha1.DefaultPluginKubeVirt,
+				)
+			}
+		}
+	}
+
+	// <namespace>/<name> : phase
+	virtualMachineInstancePhases := map[string]string{}
+	if virtualMachineInstanceList != nil {
+		for _, virtualMachineInstance := range virtualMachineInstanceList.Items {
+			phase, _, _ := unstructured.NestedString(virtualMachineInstance.Object, "status", "phase")
+			virtualMachineInstancePhases[virtualMachineInstance.GetNamespace()+"/"+virtualMachineInstance.GetName()] = phase
+		}
+	}
+	// <namespace>/<name> : PVC
+	persistentVolumeClaims := map[string]corev1.PersistentVolumeClaim{}
+	if persistentVolumeClaimList != nil {
+		for _, persistentVolumeClaim := range persistentVolumeClaimList.Items {
+			persistentVolumeClaims[persistentVolumeClaim.Namespace+"/"+persistentVolumeClaim.Name] = persistentVolumeClaim
+		}
+	}
+	// <namespace>/<VM name> : virt-launcher pod
+	virtLauncherPods := map[string]*corev1.Pod{}
+	if virtLauncherPodList != nil {
+		for _, pod := range virtLauncherPodList.Items {
+			virtLauncherPods[pod.Namespace+"/"+pod.Labels[virtualMachineNameLabel]] = &pod
+		}
+	}
+
+	// namespace : objects
+	virtualMachinesByNamespace := map[string][]unstructured.Unstructured{}
+	for _, virtualMachine := range virtualMachineList.Items {
+		virtualMachinesByNamespace[virtualMachine.GetNamespace()] = append(virtualMachinesByNamespace[virtualMachine.GetNamespace()], virtualMachine)
+	}
+	virtualMachineInstancesByNamespace := map[string][]unstructured.Unstructured{}
+	if virtualMachineInstanceList != nil {
+		for _, virtualMachineInstance := range virtualMachineInstanceList.Items {
+			virtualMachineInstancesByNamespace[virtualMachineInstance.GetNamespace()] = append(virtualMachineInstancesByNamespace[virtualMachineInstance.GetNamespace()], virtualMachineInstance)
+		}
+	}
+	dataVolumesByNamespace := map[string][]unstructured.Unstructured{}
+	if dataVolumeList != nil {
+		for _, dataVolume := range dataVolumeList.Items {
+			dataVolumesByNamespace[dataVolume.GetNamespace()] = append(dataVolumesByNamespace[dataVolume.GetNamespace()], dataVolume)
+			phase, _, _ := unstructured.NestedString(dataVolume.Object, "status", "phase")
+			// WaitForFirstConsumer and PendingPopulation DataVolumes are waiting for a pod to use them, they have no data to be incomplete
+			if !slices.Contains(dataVolumeCompletePhases, phase) {
+				summary.replaces["ERRORS"] += fmt.Sprintf(
+					"⚠️ DataVolume **%v** in **%v** namespace is in **%v** phase, its PVC may be incomplete when backed up\n\n",
+					dataVolume.GetName(), dataVolume.GetNamespace(), phase,
+				)
+			}
+		}
+	}
+
+	filesText := ""
+	summary.replaces["VIRTUALIZATION"] += "| Namespace | VirtualMachine | VirtualMachineInstance phase | PVCs (volumeMode) | Backups | freeze hooks |\n| --- | --- | --- | --- | --- | --- |\n"
+	for _, namespace := range slices.Sorted(maps.Keys(virtualMachinesByNamespace)) {
+		var persistentVolumeClaimItems []runtime.Object
+		for _, virtualMachine := range virtualMachinesByNamespace[namespace] {
+			key := namespace + "/" + virtualMachine.GetName()
+			phase, hasInstance := virtualMachineInstancePhases[key]
+			phaseText := "-"
+			if hasInstance {
+				phaseText = phase
+			}
+			running := phase == "Running"
+			virtLauncherPod := virtLauncherPods[key]
+
+			var claimsText []string
+			var blockClaims []string
+			for _, claimName := range virtualMachineClaimNames(&virtualMachine) {
+				persistentVolumeClaim, ok := persistentVolumeClaims[namespace+"/"+claimName]
+				if !ok {
+					claimsText = append(claimsText, fmt.Sprintf("❌ %s (not found)", claimName))
+					continue
+				}
+				volumeMode := string(corev1.PersistentVolumeFilesystem)
+				if persistentVolumeClaim.Spec.VolumeMode != nil {
+					volumeMode = string(*persistentVolumeClaim.Spec.VolumeMode)
+				}
+				if volumeMode == string(corev1.PersistentVolumeBlock) {
+					blockClaims = append(blockClaims, claimName)
+				}
+				claimsText = append(claimsText, fmt.Sprintf("%s (%s)", claimName, volumeMode))
+				persistentVolumeClaim.GetObjectKind().SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+				persistentVolumeClaimItems = append(persistentVolumeClaimItems, &persistentVolumeClaim)
+			}
+
+			var backups []string
+			var backupsWithoutHooks []string
+			for _, backup := range backupList.Items {
+				if !gather.BackupIncludesNamespace(&backup, namespace) {
+					continue
+				}
+				backups = append(backups, backup.Name)
+				if running && !backupHasFreezeHooks(&backup, namespace, virtLauncherPod) {
+					backupsWithoutHooks = append(backupsWithoutHooks, backup.Name)
+				}
+				usesFSBackup := backup.Spec.DefaultVolumesToFsBackup != nil && *backup.Spec.DefaultVolumesToFsBackup
+				if virtLauncherPod != nil && len(virtLauncherPod.Annotations[fsBackupVolumesAnnotation]) != 0 {
+					usesFSBackup = true
+				}
+				if usesFSBackup && len(blockClaims) != 0 {
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"❌ Backup **%v** uses File System Backup for VirtualMachine **%v** in **%v** namespace, which has PVCs in **Block** volumeMode %v, not supported by File System Backup\n\n",
+						backup.Name, virtualMachine.GetName(), namespace, blockClaims,
+					)
+				}
+			}
+
+			hooksText := "-"
+			if running && len(backups) != 0 {
+				hooksText = "✅ true"
+				if len(backupsWithoutHooks) != 0 {
+					hooksText = "⚠️ false"
+					summary.replaces["ERRORS"] += fmt.Sprintf(
+						"⚠️ Running VirtualMachine **%v** in **%v** namespace is backed up by %v without freeze/unfreeze hooks, its disks may be inconsistent\n\n",
+						virtualMachine.GetName(), namespace, backupsWithoutHooks,
+					)
+				}
+			}
+			backupsText := "-"
+			if len(backups) != 0 {
+				backupsText = strings.Join(backups, "<br>")
+			}
+
+			summary.replaces["VIRTUALIZATION"] += fmt.Sprintf(
+				"| %v | %v | %v | %s | %s | %s |\n",
+				namespace, virtualMachine.GetName(), phaseText, strings.Join(claimsText, "<br>"), backupsText, hooksText,
+			)
+		}
+
+		links := []string{summary.unstructuredYAML(outputPath, namespace, "kubevirt.io", "virtualmachines", virtualMachinesByNamespace[namespace])}
+		if len(virtualMachineInstancesByNamespace[namespace]) != 0 {
+			links = append(links, summary.unstructuredYAML(outputPath, namespace, "kubevirt.io", "virtualmachineinstances", virtualMachineInstancesByNamespace[namespace]))
+		}
+		if len(dataVolumesByNamespace[namespace]) != 0 {
+			links = append(links, summary.unstructuredYAML(outputPath, namespace, "cdi.kubevirt.io", "datavolumes", dataVolumesByNamespace[namespace]))
+		}
+		if len(persistentVolumeClaimItems) != 0 {
+			list := &corev1.List{}
+			list.GetObjectKind().SetGroupVersionKind(gvk.ListGVK)
+			for _, item := range persistentVolumeClaimItems {
+				list.Items = append(list.Items, runtime.RawExtension{Object: item})
+			}
+			file := fmt.Sprintf("namespaces/%s/core/persistentvolumeclaims/virtualmachines-persistentvolumeclaims.yaml", namespace)
+			summary.createYAML(outputPath, file, list)
+			links = append(links, fmt.Sprintf("[`virtualmachines-persistentvolumeclaims.yaml`](%s)", file))
+		}
+		filesText += fmt.Sprintf("For more information about **%v** namespace, check %s\n\n", namespace, strings.Join(links, ", "))
+	}
+	summary.replaces["VIRTUALIZATION"] += "\n" + filesText
+}